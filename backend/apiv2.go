@@ -0,0 +1,54 @@
+package main
+
+const apiVersionV2 = "v2"
+
+// toMatchV2 resolves a match's champion, items, spells, runes, queue and role against static data
+func toMatchV2(sd *StaticData, match PlayerMatchStats) MatchV2 {
+	v2 := MatchV2{
+		PlayerMatchStats: match,
+		Role:             normalizeRole(match.TeamPosition, match.GameMode),
+		Queue:            resolveQueue(sd, match.QueueID),
+		Champion:         resolveChampionRef(sd, match.ChampionID),
+		ItemRefs:         make([]StaticRef, 0, len(match.Items)),
+		SpellRefs:        make([]StaticRef, 0, len(match.SummonerSpells)),
+	}
+	if v2.Champion.Name == "" {
+		v2.Champion.Name = match.ChampionName
+	}
+	for _, itemID := range match.Items {
+		v2.ItemRefs = append(v2.ItemRefs, resolveItemRef(sd, itemID))
+	}
+	for _, spellID := range match.SummonerSpells {
+		v2.SpellRefs = append(v2.SpellRefs, resolveSummonerSpellRef(sd, spellID))
+	}
+	if match.PrimaryRune != 0 {
+		keystone := resolveRuneRef(sd, match.PrimaryRune)
+		v2.Keystone = &keystone
+	}
+	if match.SecondaryStyle != 0 {
+		tree := resolveRunePathRef(sd, match.SecondaryStyle)
+		v2.SecondaryTree = &tree
+	}
+	if match.Arena != nil {
+		for _, augmentID := range match.Arena.Augments {
+			v2.AugmentRefs = append(v2.AugmentRefs, resolveAugmentRef(sd, augmentID))
+		}
+	}
+	return v2
+}
+
+// toDashboardResponseV2 converts a v1 dashboard page to the v2 shape
+func toDashboardResponseV2(sd *StaticData, dashboard *PaginatedDashboardResponse) *DashboardResponseV2 {
+	v2 := &DashboardResponseV2{
+		APIVersion:       apiVersionV2,
+		Summary:          dashboard.Summary,
+		Matches:          make([]MatchV2, 0, len(dashboard.Matches)),
+		Pagination:       dashboard.Pagination,
+		IncrementalStats: dashboard.IncrementalStats,
+		Sessions:         dashboard.Sessions,
+	}
+	for _, match := range dashboard.Matches {
+		v2.Matches = append(v2.Matches, toMatchV2(sd, match))
+	}
+	return v2
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const aramGameMode = "ARAM"
+
+// Mark (the ARAM snowball) has its own spell in URF-style ARAM events
+var snowballSpellKeys = map[string]bool{
+	"SummonerSnowball":             true,
+	"SummonerSnowURFSnowball_Mark": true,
+}
+
+func isAramMode(gameMode string) bool {
+	return strings.ToUpper(gameMode) == aramGameMode
+}
+
+// extractAramMatchStats counts Mark casts from the summoner spell slots and hits from challenges
+func extractAramMatchStats(sd *StaticData, player *ParticipantDto) *AramMatchStats {
+	aram := &AramMatchStats{}
+	if player.Challenges != nil {
+		aram.SnowballsHit = player.Challenges.SnowballsHit
+	}
+	if sd == nil {
+		return aram
+	}
+	for _, slot := range []struct{ spellID, casts int }{
+		{player.Summoner1Id, player.Summoner1Casts},
+		{player.Summoner2Id, player.Summoner2Casts},
+	} {
+		if spell, ok := sd.SummonerSpells[strconv.Itoa(slot.spellID)]; ok && snowballSpellKeys[spell.ID] {
+			aram.SnowballsThrown += slot.casts
+		}
+	}
+	return aram
+}
+
+// calculateAramRates computes the per-minute rates shared by the ARAM and Rift comparison
+func calculateAramRates(matches []PlayerMatchStats) AramRates {
+	rates := AramRates{Games: len(matches)}
+	var gameTime, damage, healShield, deaths int64
+	for _, match := range matches {
+		gameTime += match.GameDuration
+		damage += int64(match.DamageToChampions)
+		healShield += int64(match.HealsOnTeammates + match.ShieldsOnTeammates)
+		deaths += int64(match.Deaths)
+	}
+	if gameTime == 0 {
+		return rates
+	}
+	minutes := float64(gameTime) / 60
+	rates.DamagePerMin = float64(damage) / minutes
+	rates.HealShieldOnTeammatesPerMin = float64(healShield) / minutes
+	rates.DeathsPerMin = float64(deaths) / minutes
+	return rates
+}
+
+// calculateAramSummary builds the ARAM section of the summary and compares it to the player's
+// classic games. It returns nil when there are no ARAM games.
+func calculateAramSummary(sd *StaticData, matches []PlayerMatchStats) *AramSummary {
+	var aramMatches, riftMatches []PlayerMatchStats
+	for _, match := range matches {
+		switch {
+		case isAramMode(match.GameMode):
+			aramMatches = append(aramMatches, match)
+		case isClassicMode(match.GameMode):
+			riftMatches = append(riftMatches, match)
+		}
+	}
+	if len(aramMatches) == 0 {
+		return nil
+	}
+
+	summary := &AramSummary{
+		Games:     len(aramMatches),
+		Rates:     calculateAramRates(aramMatches),
+		Champions: []AramChampionStats{},
+	}
+	if len(riftMatches) > 0 {
+		riftRates := calculateAramRates(riftMatches)
+		summary.RiftRates = &riftRates
+	}
+
+	champions := map[int]*AramChampionStats{}
+	for _, match := range aramMatches {
+		if match.Win {
+			summary.Wins++
+		}
+		if match.Aram != nil {
+			summary.SnowballsThrown += match.Aram.SnowballsThrown
+			summary.SnowballsHit += match.Aram.SnowballsHit
+		}
+
+		champ, ok := champions[match.ChampionID]
+		if !ok {
+			ref := resolveChampionRef(sd, match.ChampionID)
+			if ref.Name == "" {
+				ref.Name = match.ChampionName
+			}
+			champ = &AramChampionStats{Champion: ref}
+			champions[match.ChampionID] = champ
+		}
+		champ.Games++
+		if match.Win {
+			champ.Wins++
+		}
+	}
+
+	summary.WinRate = float64(summary.Wins) / float64(summary.Games) * 100
+	if summary.SnowballsThrown > 0 {
+		summary.SnowballHitRate = float64(summary.SnowballsHit) / float64(summary.SnowballsThrown) * 100
+	}
+	summary.SnowballHitsPerGame = float64(summary.SnowballsHit) / float64(summary.Games)
+
+	for _, champ := range champions {
+		champ.WinRate = float64(champ.Wins) / float64(champ.Games) * 100
+		summary.Champions = append(summary.Champions, *champ)
+	}
+	sort.Slice(summary.Champions, func(i, j int) bool {
+		a, b := summary.Champions[i], summary.Champions[j]
+		if a.Games != b.Games {
+			return a.Games > b.Games
+		}
+		return a.Champion.ID < b.Champion.ID
+	})
+
+	return summary
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	arenaGameMode     = "CHERRY"
+	arenaTop4Cutoff   = 4 // Placements up to this count as a top-4 finish
+	arenaAugmentSlots = 6
+)
+
+func isArenaMode(gameMode string) bool {
+	return strings.ToUpper(gameMode) == arenaGameMode
+}
+
+// extractArenaMatchStats pulls placement, augments and the duo partner for a player in an Arena match
+func extractArenaMatchStats(matchData *MatchDto, player *ParticipantDto) *ArenaMatchStats {
+	arena := &ArenaMatchStats{
+		SubteamID: player.PlayerSubteamID,
+		Placement: player.SubteamPlacement,
+		Augments:  make([]int, 0, arenaAugmentSlots),
+	}
+	for _, augmentID := range []int{player.PlayerAugment1, player.PlayerAugment2, player.PlayerAugment3, player.PlayerAugment4, player.PlayerAugment5, player.PlayerAugment6} {
+		if augmentID != 0 {
+			arena.Augments = append(arena.Augments, augmentID)
+		}
+	}
+
+	for _, p := range matchData.Info.Participants {
+		if p.PUUID != player.PUUID && p.PlayerSubteamID != 0 && p.PlayerSubteamID == player.PlayerSubteamID {
+			arena.PartnerPUUID = p.PUUID
+			arena.PartnerChampionID = p.ChampionID
+			arena.PartnerChampionName = p.ChampionName
+			break
+		}
+	}
+	return arena
+}
+
+// arenaAccumulator sums up placements for one augment, duo or the whole summary
+type arenaAccumulator struct {
+	games, firstPlaces, top4, placementSum int
+}
+
+func (a *arenaAccumulator) add(placement int) {
+	a.games++
+	a.placementSum += placement
+	if placement == 1 {
+		a.firstPlaces++
+	}
+	if placement <= arenaTop4Cutoff {
+		a.top4++
+	}
+}
+
+func (a *arenaAccumulator) rates() (winRate, top4Rate, avgPlacement float64) {
+	if a.games == 0 {
+		return 0, 0, 0
+	}
+	games := float64(a.games)
+	return float64(a.firstPlaces) / games * 100, float64(a.top4) / games * 100, float64(a.placementSum) / games
+}
+
+// calculateArenaSummary aggregates Arena games by placement. It returns nil when there are no
+// Arena games with a recorded placement.
+func calculateArenaSummary(sd *StaticData, matches []PlayerMatchStats) *ArenaSummary {
+	type duoKey struct{ championID, partnerChampionID int }
+
+	var total arenaAccumulator
+	augments := map[int]*arenaAccumulator{}
+	duos := map[duoKey]*arenaAccumulator{}
+	for _, match := range matches {
+		if match.Arena == nil || match.Arena.Placement <= 0 {
+			continue
+		}
+		placement := match.Arena.Placement
+		total.add(placement)
+
+		for _, augmentID := range match.Arena.Augments {
+			acc, ok := augments[augmentID]
+			if !ok {
+				acc = &arenaAccumulator{}
+				augments[augmentID] = acc
+			}
+			acc.add(placement)
+		}
+
+		if match.Arena.PartnerChampionID != 0 {
+			key := duoKey{match.ChampionID, match.Arena.PartnerChampionID}
+			acc, ok := duos[key]
+			if !ok {
+				acc = &arenaAccumulator{}
+				duos[key] = acc
+			}
+			acc.add(placement)
+		}
+	}
+	if total.games == 0 {
+		return nil
+	}
+
+	summary := &ArenaSummary{
+		Games:       total.games,
+		FirstPlaces: total.firstPlaces,
+		Top4:        total.top4,
+		Augments:    make([]ArenaAugmentStats, 0, len(augments)),
+		Duos:        make([]ArenaDuoStats, 0, len(duos)),
+	}
+	summary.WinRate, summary.Top4Rate, summary.AvgPlacement = total.rates()
+
+	for augmentID, acc := range augments {
+		stats := ArenaAugmentStats{
+			Augment:     resolveAugmentRef(sd, augmentID),
+			Games:       acc.games,
+			FirstPlaces: acc.firstPlaces,
+			Top4:        acc.top4,
+		}
+		stats.WinRate, stats.Top4Rate, stats.AvgPlacement = acc.rates()
+		summary.Augments = append(summary.Augments, stats)
+	}
+	sort.Slice(summary.Augments, func(i, j int) bool {
+		a, b := summary.Augments[i], summary.Augments[j]
+		if a.Games != b.Games {
+			return a.Games > b.Games
+		}
+		return a.Augment.ID < b.Augment.ID
+	})
+
+	for key, acc := range duos {
+		stats := ArenaDuoStats{
+			Champion:        resolveChampionRef(sd, key.championID),
+			PartnerChampion: resolveChampionRef(sd, key.partnerChampionID),
+			Games:           acc.games,
+			FirstPlaces:     acc.firstPlaces,
+			Top4:            acc.top4,
+		}
+		stats.WinRate, stats.Top4Rate, stats.AvgPlacement = acc.rates()
+		summary.Duos = append(summary.Duos, stats)
+	}
+	sort.Slice(summary.Duos, func(i, j int) bool {
+		a, b := summary.Duos[i], summary.Duos[j]
+		if a.Games != b.Games {
+			return a.Games > b.Games
+		}
+		if a.Champion.ID != b.Champion.ID {
+			return a.Champion.ID < b.Champion.ID
+		}
+		return a.PartnerChampion.ID < b.PartnerChampion.ID
+	})
+
+	return summary
+}
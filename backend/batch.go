@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	maxBatchPlayers          = 20
+	defaultBatchMatchCount   = 20
+	maxBatchMatchCount       = 50
+	batchTopChampions        = 3
+	maxBatchPlayersBodyBytes = 16 * 1024
+	batchWriteTimeout        = 3 * time.Minute // A full batch of uncached players takes far longer than the server WriteTimeout
+)
+
+// buildPlayerOverview reduces a player's recent matches to the batch lookup's summary
+func buildPlayerOverview(sd *StaticData, performance *UserPerformance) *PlayerOverview {
+	summary := calculateRecentGamesSummary(sd, performance.Matches, performance.PUUID, performance.Region, performance.RiotID)
+	overview := &PlayerOverview{
+		TotalMatches: summary.TotalMatches,
+		OverallStats: summary.OverallStats,
+		TopChampions: []ChampionStats{},
+		RankTier:     performance.RankTier,
+		LastUpdated:  performance.UpdatedAt,
+	}
+
+	mainRoleGames := 0
+	for role, stats := range summary.RoleStats {
+		if stats.GamesPlayed > mainRoleGames || (stats.GamesPlayed == mainRoleGames && role < overview.MainRole) {
+			overview.MainRole = role
+			mainRoleGames = stats.GamesPlayed
+		}
+	}
+
+	for _, stats := range summary.ChampionStats {
+		overview.TopChampions = append(overview.TopChampions, stats)
+	}
+	sort.Slice(overview.TopChampions, func(i, j int) bool {
+		a, b := overview.TopChampions[i], overview.TopChampions[j]
+		if a.GamesPlayed != b.GamesPlayed {
+			return a.GamesPlayed > b.GamesPlayed
+		}
+		return a.ChampionID < b.ChampionID
+	})
+	if len(overview.TopChampions) > batchTopChampions {
+		overview.TopChampions = overview.TopChampions[:batchTopChampions]
+	}
+	return overview
+}
+
+// batchPlayerKey identifies a Riot ID within a batch; Riot IDs are case-insensitive
+func batchPlayerKey(entry TrackPlayerRequest) string {
+	return strings.ToLower(strings.TrimSpace(entry.Region) + "/" + strings.TrimSpace(entry.GameName) + "#" + strings.TrimSpace(entry.TagLine))
+}
+
+// lookupBatchPlayer validates and resolves one entry; failures are recorded on the result
+// rather than returned so one bad Riot ID never fails the batch
+func lookupBatchPlayer(ctx context.Context, app *GlobalAppData, budget fetchBudget, entry TrackPlayerRequest, count, queueID int) BatchPlayerResult {
+	result := BatchPlayerResult{Region: entry.Region, GameName: entry.GameName, TagLine: entry.TagLine}
+	fail := func(apiErr *APIError) BatchPlayerResult {
+		result.Error = &ErrorBody{
+			Code:       apiErr.Code,
+			Status:     apiErr.Status,
+			Message:    apiErr.Message,
+			RetryAfter: apiErr.RetryAfter,
+			RequestID:  requestIDFromContext(ctx),
+			Details:    apiErr.Details,
+		}
+		return result
+	}
+
+	gameName, tagLine, region, err := ValidateAndSanitizeInput(entry.GameName, entry.TagLine, entry.Region)
+	if err != nil {
+		return fail(validationAPIError(err, "Invalid input"))
+	}
+	if PreventNoSQLInjection(gameName) != nil || PreventNoSQLInjection(tagLine) != nil {
+		log.Printf("Potential NoSQL injection attempt in batch entry: %s#%s", gameName, tagLine)
+		return fail(newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
+	}
+
+	performance, err := fetchAndStoreUserPerformanceWithBudget(app, budget, region, gameName, tagLine, count, queueID, 0)
+	if err != nil {
+		log.Printf("Batch: Error fetching user performance for %s#%s: %v", gameName, tagLine, err)
+		return fail(toAPIError(err, "Error fetching user performance"))
+	}
+
+	result.PUUID = performance.PUUID
+	result.Overview = buildPlayerOverview(app.staticData, performance)
+	return result
+}
+
+// batchPlayersHandler looks up several players at once. Entries resolve concurrently, with all
+// their Riot calls sharing one budget of the match fetch concurrency limit.
+func batchPlayersHandler(app *GlobalAppData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req BatchPlayersRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchPlayersBodyBytes)).Decode(&req); err != nil {
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid request body"))
+			return
+		}
+		if len(req.Players) == 0 || len(req.Players) > maxBatchPlayers {
+			writeValidationError(w, r, ValidationError{Field: "players", Message: "must contain between 1 and " + strconv.Itoa(maxBatchPlayers) + " Riot IDs"}, "Invalid players parameter")
+			return
+		}
+
+		countStr := ""
+		if req.Count != 0 {
+			countStr = strconv.Itoa(req.Count)
+		}
+		count, err := ValidateCount(countStr, defaultBatchMatchCount, maxBatchMatchCount)
+		if err != nil {
+			log.Printf("Count validation error: %v", err)
+			writeValidationError(w, r, err, "Invalid count parameter")
+			return
+		}
+
+		if app.riotAPIKey == "" {
+			log.Println("Error: RIOT_API_KEY is not set.")
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeServerMisconfigured, "Server configuration error: Riot API Key not set."))
+			return
+		}
+
+		if app.staticData == nil {
+			log.Println("Static data not yet loaded, attempting to load now.")
+			err := populateStaticData(app)
+			if err != nil {
+				log.Printf("Error populating static data on demand: %v", err)
+				writeAPIError(w, r, newAPIError(http.StatusServiceUnavailable, ErrCodeUpstreamUnavailable, "Error loading required game data. Please try again shortly."))
+				return
+			}
+		}
+
+		queueID, err := validateQueueIDParam(app, strconv.Itoa(req.QueueID))
+		if err != nil {
+			log.Printf("QueueID validation error: %v", err)
+			writeValidationError(w, r, err, "Invalid queueId parameter")
+			return
+		}
+
+		log.Printf("Handler: Received batch lookup for %d players, count: %d, queueId: %d", len(req.Players), count, queueID)
+
+		rc := http.NewResponseController(w)
+		if err := rc.SetWriteDeadline(time.Now().Add(batchWriteTimeout)); err != nil {
+			log.Printf("Batch: Could not extend write deadline: %v", err)
+		}
+
+		// Look up a Riot ID listed more than once only at its first entry
+		firstIndex := make(map[string]int, len(req.Players))
+		duplicateOf := make(map[int]int)
+		for i, entry := range req.Players {
+			key := batchPlayerKey(entry)
+			if first, ok := firstIndex[key]; ok {
+				duplicateOf[i] = first
+			} else {
+				firstIndex[key] = i
+			}
+		}
+
+		budget := newFetchBudget(getConcurrencyLimit())
+		response := BatchPlayersResponse{Results: make([]BatchPlayerResult, len(req.Players))}
+		var wg sync.WaitGroup
+		for i, entry := range req.Players {
+			if _, ok := duplicateOf[i]; ok {
+				continue
+			}
+			wg.Add(1)
+			go func(i int, entry TrackPlayerRequest) {
+				defer wg.Done()
+				response.Results[i] = lookupBatchPlayer(r.Context(), app, budget, entry, count, queueID)
+			}(i, entry)
+		}
+		wg.Wait()
+		for i, first := range duplicateOf {
+			result := response.Results[first]
+			entry := req.Players[i]
+			result.Region, result.GameName, result.TagLine = entry.Region, entry.GameName, entry.TagLine
+			response.Results[i] = result
+		}
+
+		for _, result := range response.Results {
+			if result.Error != nil {
+				response.Failed++
+			} else {
+				response.Succeeded++
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding batch lookup response: %v", err)
+		}
+	}
+}
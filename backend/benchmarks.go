@@ -0,0 +1,396 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	benchmarksCollection             = "benchmarks"
+	defaultBenchmarkRefreshInterval  = 6 * time.Hour
+	benchmarkStartupDelay            = 3 * time.Minute
+	benchmarkQueryCacheTTL           = 1 * time.Hour
+	benchmarkComputeTimeout          = 10 * time.Minute
+	benchmarkQuantileSteps           = 20 // Distributions are stored at every 5th percentile
+	benchmarkMinGames                = 3  // Games a player needs in a role to count as a sample
+	benchmarkMinTierSamples          = 30 // Below this a tier bucket is too noisy, so all tiers are used
+	benchmarkAllRoles                = "All"
+	benchmarkMetricCSPerMin          = "csPerMin"
+	benchmarkMetricVisionPerMin      = "visionPerMin"
+	benchmarkMetricDamagePerMin      = "damagePerMin"
+	benchmarkMetricGoldPerMin        = "goldPerMin"
+	benchmarkMetricKillParticipation = "killParticipation"
+	benchmarkMetricDeathsPerMin      = "deathsPerMin"
+	benchmarkMetricHealShieldPerMin  = "healShieldPerMin"
+)
+
+// benchmarkLowerIsBetter holds the metrics where a lower value beats more of the population
+var benchmarkLowerIsBetter = map[string]bool{
+	benchmarkMetricDeathsPerMin: true,
+}
+
+// getBenchmarkRefreshInterval returns how often population benchmarks are recomputed
+func getBenchmarkRefreshInterval() time.Duration {
+	if intervalStr := os.Getenv("BENCHMARK_REFRESH_INTERVAL"); intervalStr != "" {
+		if interval, err := time.ParseDuration(intervalStr); err == nil && interval >= 5*time.Minute {
+			return interval
+		}
+	}
+	return defaultBenchmarkRefreshInterval
+}
+
+func benchmarkBucketID(role, tier string) string {
+	return role + "|" + tier
+}
+
+// benchmarkMetrics averages the benchmarked metrics over a set of matches, the same way for
+// population samples and for the player being compared. CS and gold only count classic games.
+func benchmarkMetrics(matches []PlayerMatchStats) map[string]float64 {
+	var gameTime, classicGameTime int64
+	var vision, damage, deaths, healShield, classicCS, classicGold int64
+	var killParticipation float64
+
+	for _, match := range matches {
+		gameTime += match.GameDuration
+		vision += int64(match.VisionScore)
+		damage += int64(match.DamageToChampions)
+		deaths += int64(match.Deaths)
+		healShield += int64(match.HealsOnTeammates + match.ShieldsOnTeammates)
+		killParticipation += match.KillParticipation
+
+		if isClassicMode(match.GameMode) {
+			classicGameTime += match.GameDuration
+			classicCS += int64(match.TotalMinionsKilled)
+			classicGold += int64(match.GoldEarned)
+		}
+	}
+
+	metrics := map[string]float64{}
+	if len(matches) == 0 || gameTime == 0 {
+		return metrics
+	}
+	minutes := float64(gameTime) / 60
+	metrics[benchmarkMetricVisionPerMin] = float64(vision) / minutes
+	metrics[benchmarkMetricDamagePerMin] = float64(damage) / minutes
+	metrics[benchmarkMetricDeathsPerMin] = float64(deaths) / minutes
+	metrics[benchmarkMetricHealShieldPerMin] = float64(healShield) / minutes
+	metrics[benchmarkMetricKillParticipation] = killParticipation / float64(len(matches))
+	if classicGameTime > 0 {
+		classicMinutes := float64(classicGameTime) / 60
+		metrics[benchmarkMetricCSPerMin] = float64(classicCS) / classicMinutes
+		metrics[benchmarkMetricGoldPerMin] = float64(classicGold) / classicMinutes
+	}
+	return metrics
+}
+
+// quantiles returns the values at every 1/benchmarkQuantileSteps quantile of sorted samples
+func quantiles(sorted []float64) []float64 {
+	result := make([]float64, benchmarkQuantileSteps+1)
+	last := len(sorted) - 1
+	for i := range result {
+		pos := float64(i) / benchmarkQuantileSteps * float64(last)
+		lower := int(pos)
+		if lower >= last {
+			result[i] = sorted[last]
+			continue
+		}
+		frac := pos - float64(lower)
+		result[i] = sorted[lower] + (sorted[lower+1]-sorted[lower])*frac
+	}
+	return result
+}
+
+// percentileOf places a value within a quantile distribution, interpolating between quantiles
+func percentileOf(dist []float64, value float64) float64 {
+	if len(dist) == 0 {
+		return 0
+	}
+	if value <= dist[0] {
+		return 0
+	}
+	last := len(dist) - 1
+	if value >= dist[last] {
+		return 100
+	}
+
+	for i := 0; i < last; i++ {
+		if value >= dist[i+1] {
+			continue
+		}
+		frac := 0.5
+		if dist[i+1] > dist[i] {
+			frac = (value - dist[i]) / (dist[i+1] - dist[i])
+		}
+		return (float64(i) + frac) / float64(last) * 100
+	}
+	return 100
+}
+
+// startBenchmarkScheduler recomputes population benchmarks shortly after startup and then on an interval
+func startBenchmarkScheduler(ctx context.Context, app *GlobalAppData) {
+	interval := getBenchmarkRefreshInterval()
+	log.Printf("Benchmarks: Scheduler started (interval: %v)", interval)
+
+	go func() {
+		timer := time.NewTimer(benchmarkStartupDelay)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+
+			if err := computeBenchmarks(ctx, app); err != nil {
+				log.Printf("Benchmarks: Error computing benchmarks: %v", err)
+			}
+			timer.Reset(jitterDuration(interval, trackerRefreshJitter))
+		}
+	}()
+}
+
+// computeBenchmarks builds metric distributions per role and rank tier from stored players.
+// Each player contributes one sample per role they have enough games in, plus one overall.
+func computeBenchmarks(ctx context.Context, app *GlobalAppData) error {
+	ctx, cancel := context.WithTimeout(ctx, benchmarkComputeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	source := app.mongoClient.Database(app.mongoDatabase).Collection("userperformances")
+
+	projection := bson.M{
+		"rankTier":                   1,
+		"matches.gameMode":           1,
+		"matches.gameDuration":       1,
+		"matches.teamPosition":       1,
+		"matches.visionScore":        1,
+		"matches.damageToChampions":  1,
+		"matches.killParticipation":  1,
+		"matches.totalMinionsKilled": 1,
+		"matches.goldEarned":         1,
+		"matches.deaths":             1,
+		"matches.healsOnTeammates":   1,
+		"matches.shieldsOnTeammates": 1,
+	}
+	cursor, err := source.Find(ctx, bson.M{}, options.Find().SetProjection(projection))
+	if err != nil {
+		return fmt.Errorf("failed to scan user performances: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	type bucketAgg struct {
+		role, tier string
+		samples    map[string][]float64
+		count      int
+	}
+	buckets := map[string]*bucketAgg{}
+	addSample := func(role, tier string, metrics map[string]float64) {
+		id := benchmarkBucketID(role, tier)
+		agg, ok := buckets[id]
+		if !ok {
+			agg = &bucketAgg{role: role, tier: tier, samples: map[string][]float64{}}
+			buckets[id] = agg
+		}
+		agg.count++
+		for metric, value := range metrics {
+			agg.samples[metric] = append(agg.samples[metric], value)
+		}
+	}
+
+	playerCount := 0
+	for cursor.Next(ctx) {
+		var doc UserPerformance
+		if err := cursor.Decode(&doc); err != nil {
+			log.Printf("Benchmarks: Skipping undecodable document: %v", err)
+			continue
+		}
+		playerCount++
+
+		byRole := map[string][]PlayerMatchStats{benchmarkAllRoles: doc.Matches}
+		for _, match := range doc.Matches {
+			role := normalizeRole(match.TeamPosition, match.GameMode)
+			byRole[role] = append(byRole[role], match)
+		}
+
+		for role, matches := range byRole {
+			if len(matches) < benchmarkMinGames {
+				continue
+			}
+			metrics := benchmarkMetrics(matches)
+			addSample(role, "", metrics)
+			if doc.RankTier != "" {
+				addSample(role, doc.RankTier, metrics)
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed while scanning user performances: %w", err)
+	}
+
+	target := app.mongoClient.Database(app.mongoDatabase).Collection(benchmarksCollection)
+	runStamp := time.Now().Unix()
+
+	writes := make([]mongo.WriteModel, 0, len(buckets))
+	for id, agg := range buckets {
+		bucket := BenchmarkBucket{
+			ID:            id,
+			Role:          agg.role,
+			Tier:          agg.tier,
+			SampleSize:    agg.count,
+			Distributions: make(map[string][]float64, len(agg.samples)),
+			UpdatedAt:     runStamp,
+		}
+		for metric, samples := range agg.samples {
+			sort.Float64s(samples)
+			bucket.Distributions[metric] = quantiles(samples)
+		}
+		writes = append(writes, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": id}).
+			SetReplacement(bucket).
+			SetUpsert(true))
+	}
+
+	if len(writes) > 0 {
+		if _, err := target.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+			return fmt.Errorf("failed to write benchmarks: %w", err)
+		}
+	}
+
+	// Drop buckets that no longer have any samples
+	if _, err := target.DeleteMany(ctx, bson.M{"updatedAt": bson.M{"$lt": runStamp}}); err != nil {
+		log.Printf("Benchmarks: Error removing stale buckets: %v", err)
+	}
+
+	log.Printf("Benchmarks: Computed %d buckets from %d players in %v", len(buckets), playerCount, time.Since(start))
+	return nil
+}
+
+// loadBenchmarks returns the all-tiers buckets and, if tier is set, that tier's buckets, keyed by bucket ID
+func loadBenchmarks(ctx context.Context, app *GlobalAppData, tier string) (map[string]BenchmarkBucket, error) {
+	cacheKey := fmt.Sprintf("benchmarks:t%s", tier)
+
+	val, err := app.redisClient.Get(ctx, cacheKey).Result()
+	if err == nil {
+		var cached map[string]BenchmarkBucket
+		if err := json.Unmarshal([]byte(val), &cached); err == nil {
+			return cached, nil
+		}
+	} else if err != redis.Nil {
+		log.Printf("Error fetching benchmarks from Redis: %v. Proceeding to query MongoDB.", err)
+	}
+
+	collection := app.mongoClient.Database(app.mongoDatabase).Collection(benchmarksCollection)
+	cursor, err := collection.Find(ctx, bson.M{"tier": bson.M{"$in": []string{"", tier}}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query benchmarks: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var found []BenchmarkBucket
+	if err := cursor.All(ctx, &found); err != nil {
+		return nil, fmt.Errorf("failed to decode benchmarks: %w", err)
+	}
+
+	buckets := make(map[string]BenchmarkBucket, len(found))
+	for _, bucket := range found {
+		buckets[bucket.ID] = bucket
+	}
+
+	// Move Redis caching off the critical path - run asynchronously
+	go func(key string, data map[string]BenchmarkBucket) {
+		cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if dataJSON, err := json.Marshal(data); err == nil {
+			_ = app.redisClient.Set(cacheCtx, key, dataJSON, benchmarkQueryCacheTTL).Err()
+		}
+	}(cacheKey, buckets)
+
+	return buckets, nil
+}
+
+// percentileStats compares a player's matches against the best available bucket for a role:
+// their own tier when it has enough samples, otherwise all tiers
+func percentileStats(buckets map[string]BenchmarkBucket, role, tier string, matches []PlayerMatchStats) *PercentileStats {
+	bucket, ok := buckets[benchmarkBucketID(role, tier)]
+	if tier == "" || !ok || bucket.SampleSize < benchmarkMinTierSamples {
+		bucket, ok = buckets[benchmarkBucketID(role, "")]
+		if !ok {
+			return nil
+		}
+	}
+
+	stats := &PercentileStats{Tier: bucket.Tier, SampleSize: bucket.SampleSize}
+	for metric, value := range benchmarkMetrics(matches) {
+		dist, ok := bucket.Distributions[metric]
+		if !ok {
+			continue
+		}
+		mp := &MetricPercentile{Value: value, Percentile: percentileOf(dist, value)}
+		if benchmarkLowerIsBetter[metric] {
+			mp.Percentile = 100 - mp.Percentile
+		}
+		switch metric {
+		case benchmarkMetricCSPerMin:
+			stats.CSPerMin = mp
+		case benchmarkMetricVisionPerMin:
+			stats.VisionPerMin = mp
+		case benchmarkMetricDamagePerMin:
+			stats.DamagePerMin = mp
+		case benchmarkMetricGoldPerMin:
+			stats.GoldPerMin = mp
+		case benchmarkMetricKillParticipation:
+			stats.KillParticipation = mp
+		case benchmarkMetricDeathsPerMin:
+			stats.DeathsPerMin = mp
+		case benchmarkMetricHealShieldPerMin:
+			stats.HealShieldPerMin = mp
+		}
+	}
+	return stats
+}
+
+// annotatePercentiles sets Percentiles on the summary's OverallStats, RoleStats and ARAM section
+// against the player's stored tier. It is best effort: without benchmarks or a known tier the
+// summary is left as is or compared to all tiers.
+func annotatePercentiles(ctx context.Context, app *GlobalAppData, summary *RecentGamesSummary, tier string) {
+	if summary == nil || len(summary.RecentMatches) == 0 {
+		return
+	}
+
+	buckets, err := loadBenchmarks(ctx, app, tier)
+	if err != nil {
+		log.Printf("Benchmarks: Could not load benchmarks: %v", err)
+		return
+	}
+	if len(buckets) == 0 {
+		return
+	}
+
+	summary.OverallStats.Percentiles = percentileStats(buckets, benchmarkAllRoles, tier, summary.RecentMatches)
+
+	byRole := map[string][]PlayerMatchStats{}
+	for _, match := range summary.RecentMatches {
+		role := normalizeRole(match.TeamPosition, match.GameMode)
+		byRole[role] = append(byRole[role], match)
+	}
+	for role, stats := range summary.RoleStats {
+		stats.Percentiles = percentileStats(buckets, role, tier, byRole[role])
+		summary.RoleStats[role] = stats
+	}
+
+	// The ARAM section compares against the same population as the ARAM role
+	if summary.AramStats != nil {
+		summary.AramStats.Percentiles = summary.RoleStats[normalizeRole("", aramGameMode)].Percentiles
+	}
+}
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	coreBuildSize          = 3
+	maxSkillRank           = 5
+	minSkillLevelsForOrder = 9 // Enough points that at least one basic ability is clearly prioritized
+)
+
+var skillSlotKeys = map[int]string{1: "Q", 2: "W", 3: "E", 4: "R"}
+
+// isCompletedItem reports whether an item is a finished build item rather than a
+// component, consumable, trinket or boots, based on Data Dragon's build tree
+func isCompletedItem(sd *StaticData, itemID int) bool {
+	if sd == nil {
+		return false
+	}
+	item, ok := sd.Items[strconv.Itoa(itemID)]
+	if !ok {
+		return false
+	}
+	for _, tag := range item.Tags {
+		switch tag {
+		case "Consumable", "Trinket", "Boots":
+			return false
+		}
+	}
+	return len(item.Into) == 0 && item.Depth >= 2
+}
+
+// parseBuildPath replays a participant's item events in order, applying undos
+func parseBuildPath(timeline *MatchTimelineDto, participantID int) []ItemEvent {
+	path := []ItemEvent{}
+
+	for _, frame := range timeline.Info.Frames {
+		for _, event := range frame.Events {
+			if event.ParticipantID != participantID {
+				continue
+			}
+
+			switch event.Type {
+			case "ITEM_PURCHASED":
+				path = append(path, ItemEvent{ItemID: event.ItemID, Timestamp: event.Timestamp, Action: "purchased"})
+			case "ITEM_SOLD":
+				path = append(path, ItemEvent{ItemID: event.ItemID, Timestamp: event.Timestamp, Action: "sold"})
+			case "ITEM_UNDO":
+				// Undoing a purchase removes beforeId, undoing a sale restores afterId
+				action, itemID := "purchased", event.BeforeID
+				if event.BeforeID == 0 {
+					action, itemID = "sold", event.AfterID
+				}
+				for i := len(path) - 1; i >= 0; i-- {
+					if path[i].ItemID == itemID && path[i].Action == action {
+						path = append(path[:i], path[i+1:]...)
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return path
+}
+
+// parseSkillOrder returns the skill slot leveled at each champion level
+func parseSkillOrder(timeline *MatchTimelineDto, participantID int) []int {
+	order := []int{}
+
+	for _, frame := range timeline.Info.Frames {
+		for _, event := range frame.Events {
+			if event.Type != "SKILL_LEVEL_UP" || event.ParticipantID != participantID {
+				continue
+			}
+			// EVOLVE level-ups (Kha'Zix, Kai'Sa...) don't spend a skill point
+			if event.LevelUpType != "" && event.LevelUpType != "NORMAL" {
+				continue
+			}
+			order = append(order, event.SkillSlot)
+		}
+	}
+
+	return order
+}
+
+// skillMaxOrder derives the basic ability priority (e.g. "QEW") from a skill order.
+// Abilities that reached max rank come first in the order they were maxed; the rest
+// are ranked by points spent, then by which got its latest point first.
+func skillMaxOrder(order []int) string {
+	if len(order) < minSkillLevelsForOrder {
+		return ""
+	}
+
+	type slotProgress struct {
+		slot     int
+		points   int
+		maxedAt  int
+		lastSeen int
+	}
+	progress := map[int]*slotProgress{}
+	for _, slot := range []int{1, 2, 3} {
+		progress[slot] = &slotProgress{slot: slot, maxedAt: -1, lastSeen: -1}
+	}
+
+	for i, slot := range order {
+		p, ok := progress[slot]
+		if !ok {
+			continue // Ultimate
+		}
+		p.points++
+		p.lastSeen = i
+		if p.points == maxSkillRank {
+			p.maxedAt = i
+		}
+	}
+
+	slots := []*slotProgress{progress[1], progress[2], progress[3]}
+	sort.SliceStable(slots, func(i, j int) bool {
+		a, b := slots[i], slots[j]
+		if (a.maxedAt >= 0) != (b.maxedAt >= 0) {
+			return a.maxedAt >= 0
+		}
+		if a.maxedAt >= 0 {
+			return a.maxedAt < b.maxedAt
+		}
+		if a.points != b.points {
+			return a.points > b.points
+		}
+		return a.lastSeen < b.lastSeen
+	})
+
+	var sb strings.Builder
+	for _, p := range slots {
+		sb.WriteString(skillSlotKeys[p.slot])
+	}
+	return sb.String()
+}
+
+// coreItemsFromPath returns the first distinct completed items in purchase order
+func coreItemsFromPath(sd *StaticData, path []ItemEvent) []int {
+	core := []int{}
+	seen := map[int]bool{}
+
+	for _, event := range path {
+		if event.Action != "purchased" || seen[event.ItemID] || !isCompletedItem(sd, event.ItemID) {
+			continue
+		}
+		seen[event.ItemID] = true
+		core = append(core, event.ItemID)
+		if len(core) == coreBuildSize {
+			break
+		}
+	}
+
+	return core
+}
+
+// buildMatchBuild extracts a player's build path and skill order from a match timeline
+func buildMatchBuild(sd *StaticData, stats PlayerMatchStats, timeline *MatchTimelineDto, puuid string) *MatchBuild {
+	participantID := 0
+	for _, p := range timeline.Info.Participants {
+		if p.PUUID == puuid {
+			participantID = p.ParticipantID
+			break
+		}
+	}
+	if participantID == 0 {
+		return nil
+	}
+
+	path := parseBuildPath(timeline, participantID)
+	order := parseSkillOrder(timeline, participantID)
+
+	return &MatchBuild{
+		MatchID:       stats.MatchID,
+		ChampionName:  stats.ChampionName,
+		ChampionID:    stats.ChampionID,
+		Win:           stats.Win,
+		GameCreation:  stats.GameCreation,
+		BuildPath:     path,
+		CoreItems:     coreItemsFromPath(sd, path),
+		SkillOrder:    order,
+		SkillMaxOrder: skillMaxOrder(order),
+	}
+}
+
+// fetchMatchBuilds loads timelines for the given matches concurrently and extracts builds.
+// Matches whose timeline cannot be loaded are skipped.
+func fetchMatchBuilds(app *GlobalAppData, region, puuid string, matches []PlayerMatchStats) []MatchBuild {
+	g, ctx := errgroup.WithContext(context.Background())
+	g.SetLimit(getConcurrencyLimit())
+
+	builds := make([]*MatchBuild, len(matches))
+	for i, match := range matches {
+		i, match := i, match // capture loop variables
+		g.Go(func() error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			timeline, err := getMatchTimeline(app, region, match.MatchID)
+			if err != nil || timeline == nil {
+				return nil // Don't fail the entire group
+			}
+			builds[i] = buildMatchBuild(app.staticData, match, timeline, puuid)
+			return nil
+		})
+	}
+	g.Wait()
+
+	// Keep the input order (newest first) and drop failures
+	result := make([]MatchBuild, 0, len(builds))
+	for _, build := range builds {
+		if build != nil {
+			result = append(result, *build)
+		}
+	}
+	return result
+}
+
+// aggregateChampionBuilds groups match builds per champion and ranks core builds and
+// skill orders by games played, then win rate
+func aggregateChampionBuilds(sd *StaticData, builds []MatchBuild) map[string]ChampionBuildStats {
+	type variant struct {
+		items    []int
+		maxOrder string
+		games    int
+		wins     int
+	}
+	type championAgg struct {
+		championID int
+		games      int
+		coreBuilds map[string]*variant
+		skills     map[string]*variant
+	}
+
+	byChampion := map[string]*championAgg{}
+	for _, build := range builds {
+		agg, ok := byChampion[build.ChampionName]
+		if !ok {
+			agg = &championAgg{championID: build.ChampionID, coreBuilds: map[string]*variant{}, skills: map[string]*variant{}}
+			byChampion[build.ChampionName] = agg
+		}
+		agg.games++
+
+		if len(build.CoreItems) == coreBuildSize {
+			key := fmt.Sprint(build.CoreItems)
+			v, ok := agg.coreBuilds[key]
+			if !ok {
+				v = &variant{items: build.CoreItems}
+				agg.coreBuilds[key] = v
+			}
+			v.games++
+			if build.Win {
+				v.wins++
+			}
+		}
+
+		if build.SkillMaxOrder != "" {
+			v, ok := agg.skills[build.SkillMaxOrder]
+			if !ok {
+				v = &variant{maxOrder: build.SkillMaxOrder}
+				agg.skills[build.SkillMaxOrder] = v
+			}
+			v.games++
+			if build.Win {
+				v.wins++
+			}
+		}
+	}
+
+	rank := func(variants map[string]*variant) []*variant {
+		ranked := make([]*variant, 0, len(variants))
+		for _, v := range variants {
+			ranked = append(ranked, v)
+		}
+		sort.Slice(ranked, func(i, j int) bool {
+			if ranked[i].games != ranked[j].games {
+				return ranked[i].games > ranked[j].games
+			}
+			return ranked[i].wins*ranked[j].games > ranked[j].wins*ranked[i].games
+		})
+		return ranked
+	}
+
+	result := make(map[string]ChampionBuildStats, len(byChampion))
+	for name, agg := range byChampion {
+		stats := ChampionBuildStats{
+			ChampionName:  name,
+			ChampionID:    agg.championID,
+			GamesAnalyzed: agg.games,
+			CoreBuilds:    []CoreBuildStats{},
+			SkillOrders:   []SkillOrderStats{},
+		}
+
+		for _, v := range rank(agg.coreBuilds) {
+			items := make([]StaticRef, 0, len(v.items))
+			for _, itemID := range v.items {
+				items = append(items, resolveItemRef(sd, itemID))
+			}
+			stats.CoreBuilds = append(stats.CoreBuilds, CoreBuildStats{
+				Items:   items,
+				Games:   v.games,
+				Wins:    v.wins,
+				WinRate: float64(v.wins) / float64(v.games) * 100,
+			})
+		}
+
+		for _, v := range rank(agg.skills) {
+			stats.SkillOrders = append(stats.SkillOrders, SkillOrderStats{
+				SkillMaxOrder: v.maxOrder,
+				Games:         v.games,
+				Wins:          v.wins,
+				WinRate:       float64(v.wins) / float64(v.games) * 100,
+			})
+		}
+
+		result[name] = stats
+	}
+
+	return result
+}
+
+// fetchPlayerBuilds analyzes item builds and skill orders over a player's recent matches
+func fetchPlayerBuilds(app *GlobalAppData, region, gameName, tagLine string, count, queueID int) (*PlayerBuildsResponse, error) {
+	performance, err := fetchAndStoreUserPerformance(app, region, gameName, tagLine, count, queueID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user performance: %w", err)
+	}
+
+	builds := fetchMatchBuilds(app, region, performance.PUUID, performance.Matches)
+
+	return &PlayerBuildsResponse{
+		PUUID:     performance.PUUID,
+		Region:    performance.Region,
+		RiotID:    performance.RiotID,
+		Matches:   builds,
+		Champions: aggregateChampionBuilds(app.staticData, builds),
+	}, nil
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Cache-Control policies. Player data can change with every new match, so clients must
+// revalidate; static data only changes with a patch; a finished match never changes.
+const (
+	cacheControlPlayer = "private, no-cache"
+	cacheControlStatic = "public, max-age=3600"
+	cacheControlMatch  = "public, max-age=86400, immutable"
+	cacheControlNever  = "no-store"
+
+	// Suggestions only drift as matches are ingested, so a few minutes stale is fine
+	cacheControlSearch = "public, max-age=300"
+)
+
+const brotliCompressionLevel = 5 // Good ratio while staying fast enough for dynamic responses
+
+// strongETag returns a strong entity tag identifying the representation built from parts
+func strongETag(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return `"` + hex.EncodeToString(sum[:12]) + `"`
+}
+
+// playerETag identifies a player's data by its newest match and when it was last refreshed.
+// variant distinguishes representations of the same data, e.g. the API version and paging.
+func playerETag(latestMatchID string, updatedAt int64, variant ...string) string {
+	return strongETag(append([]string{latestMatchID, strconv.FormatInt(updatedAt, 10)}, variant...)...)
+}
+
+// latestMatchID returns the newest match ID of a newest-first match list
+func latestMatchID(matches []PlayerMatchStats) string {
+	if len(matches) == 0 {
+		return ""
+	}
+	return matches[0].MatchID
+}
+
+// staticDataETag identifies anything derived only from static data of the current patch
+func staticDataETag(sd *StaticData, variant ...string) string {
+	version := ""
+	if sd != nil {
+		version = sd.LatestVersion
+	}
+	return strongETag(append([]string{version}, variant...)...)
+}
+
+// etagMatches reports whether an If-None-Match header lists etag. Per RFC 9110 the comparison
+// is weak, so W/ prefixes are ignored.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// notModified sets the ETag and Cache-Control headers and, when the client already has this
+// representation, writes 304 Not Modified. Handlers return without a body when it reports true.
+func notModified(w http.ResponseWriter, r *http.Request, etag, cacheControl string) bool {
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", cacheControl)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+// Response compression
+
+var (
+	gzipWriterPool   = sync.Pool{New: func() interface{} { return gzip.NewWriter(io.Discard) }}
+	brotliWriterPool = sync.Pool{New: func() interface{} { return brotli.NewWriterLevel(io.Discard, brotliCompressionLevel) }}
+)
+
+// compressor is the part of gzip.Writer and brotli.Writer the middleware needs
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(io.Writer)
+}
+
+// negotiateEncoding picks br or gzip from Accept-Encoding, preferring br on equal weight,
+// or "" for an uncompressed response
+func negotiateEncoding(acceptEncoding string) string {
+	weights := map[string]float64{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				weight = parsed
+			}
+		}
+		weights[strings.ToLower(strings.TrimSpace(name))] = weight
+	}
+	if star, ok := weights["*"]; ok {
+		for _, name := range []string{"br", "gzip"} {
+			if _, listed := weights[name]; !listed {
+				weights[name] = star
+			}
+		}
+	}
+
+	best, bestWeight := "", 0.0
+	for _, name := range []string{"br", "gzip"} {
+		if weights[name] > bestWeight {
+			best, bestWeight = name, weights[name]
+		}
+	}
+	return best
+}
+
+// encodedETag gives each content coding its own strong ETag, as RFC 9110 requires, by tagging
+// the handler's ETag with the coding
+func encodedETag(etag, encoding string) string {
+	if encoding == "" || !strings.HasSuffix(etag, `"`) || len(etag) < 2 {
+		return etag
+	}
+	return etag[:len(etag)-1] + "-" + encoding + `"`
+}
+
+// stripEncodedETags undoes encodedETag on an If-None-Match header so handlers can compare
+// against their own ETags regardless of the coding the client cached
+func stripEncodedETags(ifNoneMatch string) string {
+	candidates := strings.Split(ifNoneMatch, ",")
+	for i, candidate := range candidates {
+		candidate = strings.TrimSpace(candidate)
+		for _, encoding := range []string{"br", "gzip"} {
+			if strings.HasSuffix(candidate, "-"+encoding+`"`) {
+				candidate = strings.TrimSuffix(candidate, "-"+encoding+`"`) + `"`
+				break
+			}
+		}
+		candidates[i] = candidate
+	}
+	return strings.Join(candidates, ", ")
+}
+
+// compressResponseWriter compresses the body once the handler has committed to a response
+// that is worth compressing
+type compressResponseWriter struct {
+	http.ResponseWriter
+	encoding    string
+	writer      compressor
+	wroteHeader bool
+}
+
+func (cw *compressResponseWriter) WriteHeader(status int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+
+	header := cw.Header()
+	if etag := header.Get("ETag"); etag != "" {
+		header.Set("ETag", encodedETag(etag, cw.encoding))
+	}
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified || header.Get("Content-Encoding") != "" {
+		cw.ResponseWriter.WriteHeader(status)
+		return
+	}
+
+	header.Set("Content-Encoding", cw.encoding)
+	header.Del("Content-Length")
+	switch cw.encoding {
+	case "br":
+		cw.writer = brotliWriterPool.Get().(*brotli.Writer)
+	default:
+		cw.writer = gzipWriterPool.Get().(*gzip.Writer)
+	}
+	cw.writer.Reset(cw.ResponseWriter)
+	cw.ResponseWriter.WriteHeader(status)
+}
+
+func (cw *compressResponseWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		if cw.Header().Get("Content-Type") == "" {
+			cw.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.writer == nil {
+		return cw.ResponseWriter.Write(b)
+	}
+	return cw.writer.Write(b)
+}
+
+// Flush keeps server-sent events flowing through the compressor
+func (cw *compressResponseWriter) Flush() {
+	if cw.writer != nil {
+		cw.writer.Flush()
+	}
+	if flusher, ok := cw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (cw *compressResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := cw.ResponseWriter.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+// Unwrap lets http.ResponseController reach the connection, e.g. to extend the write deadline
+func (cw *compressResponseWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+func (cw *compressResponseWriter) close() {
+	if cw.writer == nil {
+		return
+	}
+	cw.writer.Close()
+	switch writer := cw.writer.(type) {
+	case *brotli.Writer:
+		brotliWriterPool.Put(writer)
+	case *gzip.Writer:
+		gzipWriterPool.Put(writer)
+	}
+	cw.writer = nil
+}
+
+// compressMiddleware compresses responses with brotli or gzip, whichever the client prefers
+func compressMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", stripEncodedETags(ifNoneMatch))
+		}
+
+		cw := &compressResponseWriter{ResponseWriter: w, encoding: encoding}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
+	})
+}
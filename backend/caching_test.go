@@ -0,0 +1,48 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestCompressMiddlewareWriteDeadline checks that streaming handlers can still extend the server
+// WriteTimeout through the compressing writer
+func TestCompressMiddlewareWriteDeadline(t *testing.T) {
+	for _, encoding := range []string{"br", "gzip", ""} {
+		t.Run("encoding="+encoding, func(t *testing.T) {
+			deadlineErr := make(chan error, 1)
+			handler := compressMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				deadlineErr <- http.NewResponseController(w).SetWriteDeadline(time.Now().Add(time.Minute))
+				w.Header().Set("Content-Type", "text/plain")
+				_, _ = io.WriteString(w, "ok")
+			}))
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Set explicitly so the client doesn't ask for gzip on its own
+			req.Header.Set("Accept-Encoding", encoding)
+			if encoding == "" {
+				req.Header.Set("Accept-Encoding", "identity")
+			}
+			resp, err := server.Client().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if got := resp.Header.Get("Content-Encoding"); got != encoding {
+				t.Errorf("Content-Encoding = %q, want %q", got, encoding)
+			}
+			if err := <-deadlineErr; err != nil {
+				t.Errorf("SetWriteDeadline through compressMiddleware: %v", err)
+			}
+		})
+	}
+}
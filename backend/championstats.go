@@ -0,0 +1,516 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	ingestedMatchesCollection           = "ingestedmatches"
+	championStatsCollection             = "championstats"
+	defaultChampionStatsRefreshInterval = 6 * time.Hour
+	championStatsStartupDelay           = 2 * time.Minute
+	championStatsQueryCacheTTL          = 1 * time.Hour
+	championStatsComputeTimeout         = 15 * time.Minute
+	ingestBackfillScanCount             = 500
+	ingestedMatchKeyPrefix              = "ingested:" // Marks a cached match as ingested
+	defaultChampionStatsMinGames        = 1
+
+	championStatsKindPick  = "pick"
+	championStatsKindBan   = "ban"
+	championStatsKindTotal = "total"
+)
+
+// getChampionStatsRefreshInterval returns how often champion stats are recomputed
+func getChampionStatsRefreshInterval() time.Duration {
+	if intervalStr := os.Getenv("CHAMPION_STATS_REFRESH_INTERVAL"); intervalStr != "" {
+		if interval, err := time.ParseDuration(intervalStr); err == nil && interval >= 5*time.Minute {
+			return interval
+		}
+	}
+	return defaultChampionStatsRefreshInterval
+}
+
+// toIngestedMatch keeps every participant of a match in the compact form used for population stats
+func toIngestedMatch(region string, match *MatchDto) *IngestedMatch {
+	if match == nil || match.Metadata.MatchID == "" || len(match.Info.Participants) == 0 {
+		return nil
+	}
+
+	ingested := &IngestedMatch{
+		MatchID:      match.Metadata.MatchID,
+		Region:       region,
+		Patch:        patchFromGameVersion(match.Info.GameVersion),
+		QueueID:      match.Info.QueueID,
+		GameMode:     match.Info.GameMode,
+		GameCreation: match.Info.GameCreation,
+		GameDuration: match.Info.GameDuration,
+		Bans:         []int{},
+		Participants: make([]IngestedParticipant, 0, len(match.Info.Participants)),
+		IngestedAt:   time.Now().Unix(),
+	}
+	if ingested.Patch == "" {
+		ingested.Patch = unknownPatch
+	}
+
+	for _, team := range match.Info.Teams {
+		for _, ban := range team.Bans {
+			if ban.ChampionID > 0 {
+				ingested.Bans = append(ingested.Bans, ban.ChampionID)
+			}
+		}
+	}
+
+	for _, p := range match.Info.Participants {
+		ingested.Participants = append(ingested.Participants, IngestedParticipant{
+			PUUID:        p.PUUID,
+			ChampionID:   p.ChampionID,
+			ChampionName: p.ChampionName,
+			Role:         normalizeRole(p.TeamPosition, match.Info.GameMode),
+			TeamID:       p.TeamID,
+			Win:          p.Win,
+			Kills:        p.Kills,
+			Deaths:       p.Deaths,
+			Assists:      p.Assists,
+		})
+	}
+
+	return ingested
+}
+
+// ingestMatch stores all participants of a freshly fetched match for population statistics
+func ingestMatch(app *GlobalAppData, region string, match *MatchDto) {
+	ingested := toIngestedMatch(region, match)
+	if ingested == nil {
+		return
+	}
+	if err := PreventNoSQLInjection(ingested.MatchID); err != nil {
+		log.Printf("Ingest: Skipping match with suspicious ID %s", ingested.MatchID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection := app.mongoClient.Database(app.mongoDatabase).Collection(ingestedMatchesCollection)
+	opts := options.Replace().SetUpsert(true)
+	if _, err := collection.ReplaceOne(ctx, bson.M{"_id": ingested.MatchID}, ingested, opts); err != nil {
+		log.Printf("Ingest: Error storing match %s: %v", ingested.MatchID, err)
+	}
+
+	recordNameSightings(app, nameSightingsFromMatch(match))
+	indexSearchEntries(app, region, match)
+}
+
+// ingestMatchOnce ingests a match served from the cache unless this was done within the
+// lifetime of the cached match, so popular matches aren't rewritten on every read
+func ingestMatchOnce(app *GlobalAppData, region string, match *MatchDto) {
+	if match == nil || match.Metadata.MatchID == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	claimed, err := app.redisClient.SetNX(ctx, ingestedMatchKeyPrefix+match.Metadata.MatchID, 1, matchDetailsCacheDuration).Result()
+	cancel()
+	if err != nil || !claimed {
+		return
+	}
+	ingestMatch(app, region, match)
+}
+
+// backfillIngestedMatches ingests every stored match not ingested yet: full matches still in the
+// Redis match details cache, then the matches of every stored player, fetched again within the
+// background Riot budget when they are no longer cached
+func backfillIngestedMatches(ctx context.Context, app *GlobalAppData) {
+	backfillCachedMatches(ctx, app)
+	backfillStoredPlayerMatches(ctx, app)
+}
+
+// isIngested reports whether a match is already in the ingested matches collection
+func isIngested(ctx context.Context, collection *mongo.Collection, matchID string) bool {
+	return collection.FindOne(ctx, bson.M{"_id": matchID}, options.FindOne().SetProjection(bson.M{"_id": 1})).Err() == nil
+}
+
+// backfillStoredPlayerMatches ingests the matches of every player in the userperformances collection
+func backfillStoredPlayerMatches(ctx context.Context, app *GlobalAppData) {
+	ingested := app.mongoClient.Database(app.mongoDatabase).Collection(ingestedMatchesCollection)
+	performances := app.mongoClient.Database(app.mongoDatabase).Collection("userperformances")
+	start := time.Now()
+	ingestedCount := 0
+
+	opts := options.Find().SetProjection(bson.M{"region": 1, "matches.matchId": 1})
+	cursor, err := performances.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		log.Printf("Ingest: Backfill of stored players failed: %v", err)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	budget := backgroundRiotBudget()
+	seen := map[string]bool{}
+	for cursor.Next(ctx) {
+		var doc struct {
+			Region  string `bson:"region"`
+			Matches []struct {
+				MatchID string `bson:"matchId"`
+			} `bson:"matches"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			continue
+		}
+		for _, m := range doc.Matches {
+			if m.MatchID == "" || seen[m.MatchID] {
+				continue
+			}
+			seen[m.MatchID] = true
+			if PreventNoSQLInjection(m.MatchID) != nil || isIngested(ctx, ingested, m.MatchID) {
+				continue
+			}
+
+			// A cache miss costs a Riot call, so every lookup is charged
+			if err := budget.reserve(ctx, 1); err != nil {
+				return
+			}
+			// getMatchDetails ingests what it loads, whether from the cache or from Riot
+			if match, err := getMatchDetails(app, doc.Region, m.MatchID); err == nil && match != nil {
+				ingestedCount++
+			}
+		}
+	}
+
+	log.Printf("Ingest: Backfilled %d matches of stored players in %v", ingestedCount, time.Since(start))
+}
+
+// backfillCachedMatches ingests full matches still sitting in the Redis match details cache,
+// covering matches fetched before ingestion existed
+func backfillCachedMatches(ctx context.Context, app *GlobalAppData) {
+	collection := app.mongoClient.Database(app.mongoDatabase).Collection(ingestedMatchesCollection)
+	start := time.Now()
+	ingestedCount := 0
+
+	var cursor uint64
+	for {
+		keys, next, err := app.redisClient.Scan(ctx, cursor, "matchdetails:*", ingestBackfillScanCount).Result()
+		if err != nil {
+			log.Printf("Ingest: Backfill scan failed: %v", err)
+			return
+		}
+
+		for _, key := range keys {
+			// Keys look like matchdetails:{apiRegion}:{matchId}
+			parts := strings.SplitN(key, ":", 3)
+			if len(parts) != 3 {
+				continue
+			}
+			matchID := parts[2]
+
+			if isIngested(ctx, collection, matchID) {
+				continue
+			}
+
+			val, err := app.redisClient.Get(ctx, key).Result()
+			if err != nil {
+				continue
+			}
+			var match MatchDto
+			if err := json.Unmarshal([]byte(val), &match); err != nil {
+				continue
+			}
+
+			// The platform region is the lowercase prefix of the match ID, e.g. NA1_123 -> na1
+			region := ""
+			if idx := strings.Index(matchID, "_"); idx > 0 {
+				region = strings.ToLower(matchID[:idx])
+			}
+			ingestMatch(app, region, &match)
+			ingestedCount++
+		}
+
+		cursor = next
+		if cursor == 0 || ctx.Err() != nil {
+			break
+		}
+	}
+
+	log.Printf("Ingest: Backfilled %d matches from the Redis cache in %v", ingestedCount, time.Since(start))
+}
+
+// startChampionStatsScheduler backfills ingested matches once, then recomputes champion stats on an interval
+func startChampionStatsScheduler(ctx context.Context, app *GlobalAppData) {
+	interval := getChampionStatsRefreshInterval()
+	log.Printf("Champion stats: Scheduler started (interval: %v)", interval)
+
+	go func() {
+		timer := time.NewTimer(championStatsStartupDelay)
+		defer timer.Stop()
+		backfilled := false
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+
+			if !backfilled {
+				backfillIngestedMatches(ctx, app)
+				backfilled = true
+			}
+			if err := computeChampionStats(ctx, app); err != nil {
+				log.Printf("Champion stats: Error computing champion stats: %v", err)
+			}
+			timer.Reset(jitterDuration(interval, trackerRefreshJitter))
+		}
+	}()
+}
+
+// computeChampionStats scans every ingested match once and rewrites the champion stats buckets
+func computeChampionStats(ctx context.Context, app *GlobalAppData) error {
+	ctx, cancel := context.WithTimeout(ctx, championStatsComputeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	source := app.mongoClient.Database(app.mongoDatabase).Collection(ingestedMatchesCollection)
+	cursor, err := source.Find(ctx, bson.M{})
+	if err != nil {
+		return fmt.Errorf("failed to scan ingested matches: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	buckets := map[string]*ChampionStatsBucket{}
+	bucket := func(id string, init ChampionStatsBucket) *ChampionStatsBucket {
+		b, ok := buckets[id]
+		if !ok {
+			init.ID = id
+			b = &init
+			buckets[id] = b
+		}
+		return b
+	}
+
+	matchCount := 0
+	for cursor.Next(ctx) {
+		var match IngestedMatch
+		if err := cursor.Decode(&match); err != nil {
+			log.Printf("Champion stats: Skipping undecodable match: %v", err)
+			continue
+		}
+		matchCount++
+
+		total := bucket(fmt.Sprintf("total|%s|%d", match.Patch, match.QueueID), ChampionStatsBucket{
+			Kind: championStatsKindTotal, Patch: match.Patch, QueueID: match.QueueID,
+		})
+		total.Count++
+
+		// A champion banned by both teams still counts as one ban for the match
+		bannedInMatch := map[int]bool{}
+		for _, championID := range match.Bans {
+			if bannedInMatch[championID] {
+				continue
+			}
+			bannedInMatch[championID] = true
+			ban := bucket(fmt.Sprintf("ban|%d|%s|%d", championID, match.Patch, match.QueueID), ChampionStatsBucket{
+				Kind: championStatsKindBan, ChampionID: championID, Patch: match.Patch, QueueID: match.QueueID,
+			})
+			ban.Count++
+		}
+
+		for _, p := range match.Participants {
+			pick := bucket(fmt.Sprintf("pick|%d|%s|%s|%d", p.ChampionID, p.Role, match.Patch, match.QueueID), ChampionStatsBucket{
+				Kind: championStatsKindPick, ChampionID: p.ChampionID, ChampionName: p.ChampionName,
+				Role: p.Role, Patch: match.Patch, QueueID: match.QueueID,
+			})
+			pick.Count++
+			if p.Win {
+				pick.Wins++
+			}
+			pick.Kills += p.Kills
+			pick.Deaths += p.Deaths
+			pick.Assists += p.Assists
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed while scanning ingested matches: %w", err)
+	}
+
+	target := app.mongoClient.Database(app.mongoDatabase).Collection(championStatsCollection)
+	runStamp := time.Now().Unix()
+
+	writes := make([]mongo.WriteModel, 0, len(buckets))
+	for _, b := range buckets {
+		b.UpdatedAt = runStamp
+		writes = append(writes, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": b.ID}).
+			SetReplacement(b).
+			SetUpsert(true))
+	}
+
+	if len(writes) > 0 {
+		if _, err := target.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+			return fmt.Errorf("failed to write champion stats: %w", err)
+		}
+	}
+
+	// Drop buckets that no longer have any games
+	if _, err := target.DeleteMany(ctx, bson.M{"updatedAt": bson.M{"$lt": runStamp}}); err != nil {
+		log.Printf("Champion stats: Error removing stale buckets: %v", err)
+	}
+
+	log.Printf("Champion stats: Computed %d buckets from %d matches in %v", len(buckets), matchCount, time.Since(start))
+	return nil
+}
+
+// queryChampionStats merges the precomputed buckets matching the filters into per-champion, per-role rows
+func queryChampionStats(ctx context.Context, app *GlobalAppData, filters ChampionTierFilters) (*ChampionTierResponse, error) {
+	cacheKey := fmt.Sprintf("championstats:c%d:r%s:p%s:q%d:m%d", filters.ChampionID, filters.Role, filters.Patch, filters.QueueID, filters.MinGames)
+
+	val, err := app.redisClient.Get(ctx, cacheKey).Result()
+	if err == nil {
+		var cached ChampionTierResponse
+		if err := json.Unmarshal([]byte(val), &cached); err == nil {
+			return &cached, nil
+		}
+	} else if err != redis.Nil {
+		log.Printf("Error fetching champion stats from Redis: %v. Proceeding to query MongoDB.", err)
+	}
+
+	// Match totals are never narrowed by champion or role, they are the pick and ban rate denominator
+	scoped := func(kind string) bson.M {
+		query := bson.M{"kind": kind}
+		if filters.Patch != "" {
+			query["patch"] = filters.Patch
+		}
+		if filters.QueueID != 0 {
+			query["queueId"] = filters.QueueID
+		}
+		return query
+	}
+	totalQuery := scoped(championStatsKindTotal)
+	banQuery := scoped(championStatsKindBan)
+	pickQuery := scoped(championStatsKindPick)
+	if filters.ChampionID != 0 {
+		banQuery["championId"] = filters.ChampionID
+		pickQuery["championId"] = filters.ChampionID
+	}
+	if filters.Role != "" {
+		pickQuery["role"] = filters.Role
+	}
+
+	collection := app.mongoClient.Database(app.mongoDatabase).Collection(championStatsCollection)
+	load := func(query bson.M) ([]ChampionStatsBucket, error) {
+		cursor, err := collection.Find(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query champion stats: %w", err)
+		}
+		defer cursor.Close(ctx)
+		var result []ChampionStatsBucket
+		if err := cursor.All(ctx, &result); err != nil {
+			return nil, fmt.Errorf("failed to decode champion stats: %w", err)
+		}
+		return result, nil
+	}
+
+	totals, err := load(totalQuery)
+	if err != nil {
+		return nil, err
+	}
+	bans, err := load(banQuery)
+	if err != nil {
+		return nil, err
+	}
+	picks, err := load(pickQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ChampionTierResponse{Filters: filters, Champions: []ChampionTierEntry{}}
+	for _, t := range totals {
+		response.Matches += t.Count
+		if t.UpdatedAt > response.GeneratedAt {
+			response.GeneratedAt = t.UpdatedAt
+		}
+	}
+
+	bansByChampion := map[int]int{}
+	for _, b := range bans {
+		bansByChampion[b.ChampionID] += b.Count
+	}
+
+	type rowKey struct {
+		championID int
+		role       string
+	}
+	rows := map[rowKey]*ChampionStatsBucket{}
+	for i := range picks {
+		p := &picks[i]
+		key := rowKey{p.ChampionID, p.Role}
+		row, ok := rows[key]
+		if !ok {
+			row = &ChampionStatsBucket{ChampionID: p.ChampionID, ChampionName: p.ChampionName, Role: p.Role}
+			rows[key] = row
+		}
+		row.Count += p.Count
+		row.Wins += p.Wins
+		row.Kills += p.Kills
+		row.Deaths += p.Deaths
+		row.Assists += p.Assists
+	}
+
+	for _, row := range rows {
+		if row.Count < filters.MinGames {
+			continue
+		}
+
+		entry := ChampionTierEntry{
+			Champion:   resolveChampionRef(app.staticData, row.ChampionID),
+			Role:       row.Role,
+			Games:      row.Count,
+			Wins:       row.Wins,
+			WinRate:    float64(row.Wins) / float64(row.Count) * 100,
+			AvgKills:   float64(row.Kills) / float64(row.Count),
+			AvgDeaths:  float64(row.Deaths) / float64(row.Count),
+			AvgAssists: float64(row.Assists) / float64(row.Count),
+		}
+		if entry.Champion.Name == "" {
+			entry.Champion.Name = row.ChampionName
+		}
+		if row.Deaths > 0 {
+			entry.AvgKDA = float64(row.Kills+row.Assists) / float64(row.Deaths)
+		} else {
+			entry.AvgKDA = float64(row.Kills + row.Assists)
+		}
+		if response.Matches > 0 {
+			entry.PickRate = float64(row.Count) / float64(response.Matches) * 100
+			entry.BanRate = float64(bansByChampion[row.ChampionID]) / float64(response.Matches) * 100
+		}
+		response.Champions = append(response.Champions, entry)
+	}
+
+	sort.Slice(response.Champions, func(i, j int) bool {
+		if response.Champions[i].Games != response.Champions[j].Games {
+			return response.Champions[i].Games > response.Champions[j].Games
+		}
+		return response.Champions[i].Champion.ID < response.Champions[j].Champion.ID
+	})
+
+	// Move Redis caching off the critical path - run asynchronously
+	go func(key string, data ChampionTierResponse) {
+		cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if dataJSON, err := json.Marshal(data); err == nil {
+			_ = app.redisClient.Set(cacheCtx, key, dataJSON, championStatsQueryCacheTTL).Err()
+		}
+	}(cacheKey, *response)
+
+	return response, nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// GraphQL resolves each match of a list independently. matchDetailsLoader collects the
+// getMatchDetails calls they make during a short window and runs them as one batch, sharing
+// the match fetch concurrency limit, and memoizes results for the rest of the request.
+
+const (
+	matchLoaderWait     = 5 * time.Millisecond
+	matchLoaderMaxBatch = 100
+)
+
+type matchLoaderKey struct {
+	region  string
+	matchID string
+}
+
+type matchLoad struct {
+	done  chan struct{}
+	match *MatchDto
+	err   error
+}
+
+type matchDetailsLoader struct {
+	app *GlobalAppData
+
+	mu      sync.Mutex
+	loads   map[matchLoaderKey]*matchLoad
+	pending []matchLoaderKey
+	timer   *time.Timer
+}
+
+func newMatchDetailsLoader(app *GlobalAppData) *matchDetailsLoader {
+	return &matchDetailsLoader{app: app, loads: map[matchLoaderKey]*matchLoad{}}
+}
+
+type matchDetailsLoaderContextKey struct{}
+
+func withMatchDetailsLoader(ctx context.Context, loader *matchDetailsLoader) context.Context {
+	return context.WithValue(ctx, matchDetailsLoaderContextKey{}, loader)
+}
+
+// matchDetailsLoaderFrom returns the request's loader, or an unshared one when there is none
+func matchDetailsLoaderFrom(ctx context.Context, app *GlobalAppData) *matchDetailsLoader {
+	if loader, ok := ctx.Value(matchDetailsLoaderContextKey{}).(*matchDetailsLoader); ok {
+		return loader
+	}
+	return newMatchDetailsLoader(app)
+}
+
+// Load returns the match once the batch it joined has been fetched. Like getMatchDetails it
+// returns nil without an error for a match Riot doesn't know.
+func (l *matchDetailsLoader) Load(ctx context.Context, region, matchID string) (*MatchDto, error) {
+	key := matchLoaderKey{region: region, matchID: matchID}
+
+	l.mu.Lock()
+	load, ok := l.loads[key]
+	if !ok {
+		load = &matchLoad{done: make(chan struct{})}
+		l.loads[key] = load
+		l.pending = append(l.pending, key)
+		if len(l.pending) >= matchLoaderMaxBatch {
+			batch := l.takePendingLocked()
+			go l.dispatch(batch)
+		} else if l.timer == nil {
+			l.timer = time.AfterFunc(matchLoaderWait, l.flush)
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-load.done:
+		return load.match, load.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (l *matchDetailsLoader) takePendingLocked() []matchLoaderKey {
+	batch := l.pending
+	l.pending = nil
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+	return batch
+}
+
+func (l *matchDetailsLoader) flush() {
+	l.mu.Lock()
+	batch := l.takePendingLocked()
+	l.mu.Unlock()
+	if len(batch) > 0 {
+		l.dispatch(batch)
+	}
+}
+
+// dispatch fetches a batch concurrently within the match fetch concurrency limit
+func (l *matchDetailsLoader) dispatch(batch []matchLoaderKey) {
+	log.Printf("GraphQL: Loading %d matches in one batch", len(batch))
+
+	var g errgroup.Group
+	g.SetLimit(getConcurrencyLimit())
+	for _, key := range batch {
+		l.mu.Lock()
+		load := l.loads[key]
+		l.mu.Unlock()
+
+		key := key
+		g.Go(func() error {
+			load.match, load.err = getMatchDetails(l.app, key.region, key.matchID)
+			close(load.done)
+			return nil
+		})
+	}
+	g.Wait()
+}
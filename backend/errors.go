@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Stable error codes returned in API error responses. Clients should branch on these rather
+// than on messages, which may change.
+const (
+	ErrCodeInvalidParameter    = "INVALID_PARAMETER"
+	ErrCodeInvalidRegion       = "INVALID_REGION"
+	ErrCodeUnauthorized        = "UNAUTHORIZED"
+	ErrCodePlayerNotFound      = "PLAYER_NOT_FOUND"
+	ErrCodeMatchNotFound       = "MATCH_NOT_FOUND"
+	ErrCodeNotFound            = "NOT_FOUND"
+	ErrCodeRiotRateLimited     = "RIOT_RATE_LIMITED"
+	ErrCodeUpstreamUnavailable = "UPSTREAM_UNAVAILABLE"
+	ErrCodeUpstreamTimeout     = "UPSTREAM_TIMEOUT"
+	ErrCodeServerMisconfigured = "SERVER_MISCONFIGURED"
+	ErrCodeInternal            = "INTERNAL_ERROR"
+)
+
+// Riot endpoints named in RiotAPIError, used to tell a missing player from a missing match
+const (
+	riotEndpointAccount  = "account"
+	riotEndpointMatchIDs = "match-ids"
+	riotEndpointMatch    = "match"
+	riotEndpointTimeline = "timeline"
+	riotEndpointLeague   = "league"
+)
+
+const defaultRateLimitRetryAfter = 10 // Seconds, when Riot doesn't send Retry-After
+
+// RiotAPIError is a non-200 response from the Riot API. The response body is logged where the
+// error is created and deliberately not carried, so it can never reach a client.
+type RiotAPIError struct {
+	Endpoint   string
+	StatusCode int
+	RetryAfter int // Seconds, from Riot's Retry-After header on 429s
+}
+
+func (e *RiotAPIError) Error() string {
+	return fmt.Sprintf("riot %s request failed with status %d", e.Endpoint, e.StatusCode)
+}
+
+// newRiotAPIError logs the response body for debugging and returns an error without it
+func newRiotAPIError(endpoint string, resp *http.Response, body []byte) *RiotAPIError {
+	log.Printf("Riot %s request failed with status %d: %s", endpoint, resp.StatusCode, string(body))
+	riotErr := &RiotAPIError{Endpoint: endpoint, StatusCode: resp.StatusCode}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		riotErr.RetryAfter = seconds
+	}
+	return riotErr
+}
+
+// APIError is an error as returned to API clients
+type APIError struct {
+	Status     int
+	Code       string
+	Message    string
+	RetryAfter int // Seconds, 0 when there is no hint
+	Details    []ErrorDetail
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s (%d): %s", e.Code, e.Status, e.Message)
+}
+
+func newAPIError(status int, code, message string) *APIError {
+	return &APIError{Status: status, Code: code, Message: message}
+}
+
+// validationAPIError turns a validation failure into a 400 with per-field details.
+// Region failures get their own code since clients usually handle them separately.
+func validationAPIError(err error, message string) *APIError {
+	// Validation that depends on upstream data can fail for reasons other than bad input
+	var upstreamErr *APIError
+	if errors.As(err, &upstreamErr) {
+		return upstreamErr
+	}
+
+	apiErr := newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, message)
+	var validationErr ValidationError
+	if errors.As(err, &validationErr) {
+		apiErr.Details = []ErrorDetail{{Field: validationErr.Field, Message: validationErr.Message}}
+		if validationErr.Field == "region" {
+			apiErr.Code = ErrCodeInvalidRegion
+		}
+	} else if err != nil {
+		apiErr.Details = []ErrorDetail{{Message: err.Error()}}
+	}
+	return apiErr
+}
+
+// toAPIError maps an error from the fetch layer to a client-facing error. Anything it doesn't
+// recognize becomes a 500 with the fallback message, so internal details never leak.
+func toAPIError(err error, fallbackMessage string) *APIError {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	var validationErr ValidationError
+	if errors.As(err, &validationErr) {
+		return validationAPIError(err, fallbackMessage)
+	}
+
+	var riotErr *RiotAPIError
+	if errors.As(err, &riotErr) {
+		switch {
+		case riotErr.StatusCode == http.StatusNotFound:
+			switch riotErr.Endpoint {
+			case riotEndpointAccount:
+				return newAPIError(http.StatusNotFound, ErrCodePlayerNotFound, "Player not found")
+			case riotEndpointMatch, riotEndpointTimeline:
+				return newAPIError(http.StatusNotFound, ErrCodeMatchNotFound, "Match not found")
+			}
+			return newAPIError(http.StatusNotFound, ErrCodeNotFound, "Resource not found")
+		case riotErr.StatusCode == http.StatusTooManyRequests:
+			apiErr := newAPIError(http.StatusTooManyRequests, ErrCodeRiotRateLimited, "Riot API rate limit reached, please try again shortly")
+			apiErr.RetryAfter = riotErr.RetryAfter
+			if apiErr.RetryAfter == 0 {
+				apiErr.RetryAfter = defaultRateLimitRetryAfter
+			}
+			return apiErr
+		case riotErr.StatusCode == http.StatusBadRequest:
+			return newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "The request was rejected by the Riot API")
+		default:
+			// 401/403 mean our key is bad or expired; to the client that's the same as an outage
+			return newAPIError(http.StatusBadGateway, ErrCodeUpstreamUnavailable, "The Riot API is unavailable, please try again later")
+		}
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return newAPIError(http.StatusGatewayTimeout, ErrCodeUpstreamTimeout, "The request timed out, please try again")
+	}
+	if netErr != nil {
+		return newAPIError(http.StatusBadGateway, ErrCodeUpstreamUnavailable, "The Riot API is unavailable, please try again later")
+	}
+
+	return newAPIError(http.StatusInternalServerError, ErrCodeInternal, fallbackMessage)
+}
+
+// writeAPIError writes apiErr as a JSON error response
+func writeAPIError(w http.ResponseWriter, r *http.Request, apiErr *APIError) {
+	body := ErrorResponse{Error: ErrorBody{
+		Code:       apiErr.Code,
+		Status:     apiErr.Status,
+		Message:    apiErr.Message,
+		RetryAfter: apiErr.RetryAfter,
+		RequestID:  requestIDFromContext(r.Context()),
+		Details:    apiErr.Details,
+	}}
+
+	if apiErr.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(apiErr.RetryAfter))
+	}
+	// Errors are never cached, and must not inherit an ETag set before the failure
+	w.Header().Del("ETag")
+	w.Header().Set("Cache-Control", cacheControlNever)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(apiErr.Status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("Error encoding error response: %v", err)
+	}
+}
+
+// writeError maps err with toAPIError and writes it
+func writeError(w http.ResponseWriter, r *http.Request, err error, fallbackMessage string) {
+	writeAPIError(w, r, toAPIError(err, fallbackMessage))
+}
+
+// writeValidationError writes a 400 for a rejected request parameter
+func writeValidationError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	writeAPIError(w, r, validationAPIError(err, message))
+}
+
+// Request IDs tie a client-visible error to the server logs
+
+type requestIDContextKey struct{}
+
+const (
+	requestIDHeader    = "X-Request-ID"
+	maxRequestIDLength = 64
+)
+
+// requestIDMiddleware reuses a well-formed incoming X-Request-ID or generates one, and echoes it
+// on the response
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(requestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = generateRequestID()
+		}
+		w.Header().Set(requestIDHeader, requestID)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDContextKey{}, requestID)))
+	})
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+func generateRequestID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(buf)
+}
+
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey{}).(string)
+	return id
+}
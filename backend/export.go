@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	exportFormatCSV     = "csv"
+	exportFormatNDJSON  = "ndjson"
+	exportFormatParquet = "parquet"
+	exportFlushEvery    = 100  // Rows written between flushes to the client
+	exportRowGroupSize  = 1000 // Parquet rows buffered before a row group is written out
+	exportWriteTimeout  = 5 * time.Minute
+	exportListSeparator = "|" // Joins item, spell and rune names in a single CSV cell
+)
+
+var exportCSVHeader = []string{
+	"matchId", "gameCreation", "gameDuration", "gameMode", "queueId", "patch",
+	"championId", "championName", "role", "win", "kills", "deaths", "assists", "kda",
+	"killParticipation", "cs", "visionScore", "goldEarned", "damageToChampions",
+	"damageToTurrets", "damageToObjectives", "totalDamageTaken", "champLevel", "teamId",
+	"items", "summonerSpells", "keystone", "primaryStyle", "secondaryStyle", "runes",
+	"performanceScore", "performanceGrade", "performanceBadge",
+}
+
+// exportContentTypes maps export formats to their Content-Type
+var exportContentTypes = map[string]string{
+	exportFormatCSV:     "text/csv; charset=utf-8",
+	exportFormatNDJSON:  "application/x-ndjson",
+	exportFormatParquet: "application/vnd.apache.parquet",
+}
+
+// toMatchExportRow flattens a match and resolves item, spell and rune names
+func toMatchExportRow(sd *StaticData, match PlayerMatchStats) MatchExportRow {
+	row := MatchExportRow{
+		MatchID:            match.MatchID,
+		GameCreation:       match.GameCreation,
+		GameDuration:       match.GameDuration,
+		GameMode:           match.GameMode,
+		QueueID:            match.QueueID,
+		Patch:              match.Patch,
+		ChampionID:         match.ChampionID,
+		ChampionName:       match.ChampionName,
+		Role:               normalizeRole(match.TeamPosition, match.GameMode),
+		Win:                match.Win,
+		Kills:              match.Kills,
+		Deaths:             match.Deaths,
+		Assists:            match.Assists,
+		KDA:                match.KDA,
+		KillParticipation:  match.KillParticipation,
+		CS:                 match.TotalMinionsKilled,
+		VisionScore:        match.VisionScore,
+		GoldEarned:         match.GoldEarned,
+		DamageToChampions:  match.DamageToChampions,
+		DamageToTurrets:    match.DamageToTurrets,
+		DamageToObjectives: match.DamageToObjectives,
+		TotalDamageTaken:   match.TotalDamageTaken,
+		ChampLevel:         match.ChampLevel,
+		TeamID:             match.TeamID,
+		Items:              []string{},
+		SummonerSpells:     []string{},
+		Keystone:           resolveRuneRef(sd, match.PrimaryRune).Name,
+		SecondaryStyle:     resolveRunePathRef(sd, match.SecondaryStyle).Name,
+		Runes:              []string{},
+		PerformanceScore:   match.PerformanceScore,
+		PerformanceGrade:   match.PerformanceGrade,
+		PerformanceBadge:   match.PerformanceBadge,
+	}
+
+	for _, itemID := range match.Items {
+		if itemID != 0 {
+			row.Items = append(row.Items, resolveItemRef(sd, itemID).Name)
+		}
+	}
+	for _, spellID := range match.SummonerSpells {
+		row.SummonerSpells = append(row.SummonerSpells, resolveSummonerSpellRef(sd, spellID).Name)
+	}
+	if match.RunePage != nil {
+		row.PrimaryStyle = resolveRunePathRef(sd, match.RunePage.PrimaryStyle).Name
+		for _, perk := range match.RunePage.Perks {
+			row.Runes = append(row.Runes, resolveRuneRef(sd, perk).Name)
+		}
+		for _, shard := range []int{match.RunePage.StatPerks.Offense, match.RunePage.StatPerks.Flex, match.RunePage.StatPerks.Defense} {
+			row.Runes = append(row.Runes, resolveStatShardRef(shard).Name)
+		}
+	}
+
+	return row
+}
+
+func exportCSVRecord(row MatchExportRow) []string {
+	formatFloat := func(f float64) string { return strconv.FormatFloat(f, 'f', 4, 64) }
+	return []string{
+		row.MatchID, strconv.FormatInt(row.GameCreation, 10), strconv.FormatInt(row.GameDuration, 10),
+		row.GameMode, strconv.Itoa(row.QueueID), row.Patch,
+		strconv.Itoa(row.ChampionID), row.ChampionName, row.Role, strconv.FormatBool(row.Win),
+		strconv.Itoa(row.Kills), strconv.Itoa(row.Deaths), strconv.Itoa(row.Assists), formatFloat(row.KDA),
+		formatFloat(row.KillParticipation), strconv.Itoa(row.CS), strconv.Itoa(row.VisionScore),
+		strconv.Itoa(row.GoldEarned), strconv.Itoa(row.DamageToChampions),
+		strconv.Itoa(row.DamageToTurrets), strconv.Itoa(row.DamageToObjectives),
+		strconv.Itoa(row.TotalDamageTaken), strconv.Itoa(row.ChampLevel), strconv.Itoa(row.TeamID),
+		strings.Join(row.Items, exportListSeparator), strings.Join(row.SummonerSpells, exportListSeparator),
+		row.Keystone, row.PrimaryStyle, row.SecondaryStyle, strings.Join(row.Runes, exportListSeparator),
+		formatFloat(row.PerformanceScore), row.PerformanceGrade, row.PerformanceBadge,
+	}
+}
+
+// openExportCursor unwinds a player's stored matches so they can be read one at a time,
+// newest first, instead of loading the whole document
+func openExportCursor(ctx context.Context, app *GlobalAppData, region, puuid string, filters ExportFilters) (*mongo.Cursor, error) {
+	matchFilter := bson.M{}
+	if filters.QueueID != 0 {
+		matchFilter["queueId"] = filters.QueueID
+	}
+	creation := bson.M{}
+	if filters.StartTime > 0 {
+		creation["$gte"] = filters.StartTime * 1000
+	}
+	if filters.EndTime > 0 {
+		creation["$lte"] = filters.EndTime * 1000
+	}
+	if len(creation) > 0 {
+		matchFilter["gameCreation"] = creation
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"_id": puuid, "region": region}}},
+		{{Key: "$unwind", Value: "$matches"}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$matches"}}},
+		{{Key: "$match", Value: matchFilter}},
+		{{Key: "$sort", Value: bson.M{"gameCreation": -1}}},
+	}
+
+	collection := app.mongoClient.Database(app.mongoDatabase).Collection("userperformances")
+	cursor, err := collection.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true).SetBatchSize(exportFlushEvery))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stored matches: %w", err)
+	}
+	return cursor, nil
+}
+
+// streamMatchExport writes the player's stored matches in the requested format as they are
+// read from MongoDB. Headers are already sent when rows start flowing, so errors after that
+// point can only end the response early.
+// contentDisposition names an attachment for every client: filename* carries the UTF-8 name
+// (RFC 6266/5987) and filename an ASCII stand-in for clients that don't read filename*
+func contentDisposition(filename string) string {
+	var fallback, encoded strings.Builder
+	for _, r := range filename {
+		if r < 0x20 || r > 0x7e || r == '"' || r == '\\' {
+			fallback.WriteByte('_')
+		} else {
+			fallback.WriteRune(r)
+		}
+	}
+	for _, b := range []byte(filename) {
+		switch {
+		case b >= 'a' && b <= 'z', b >= 'A' && b <= 'Z', b >= '0' && b <= '9', strings.IndexByte("!#$&+-.^_`|~", b) >= 0:
+			encoded.WriteByte(b)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+	return fmt.Sprintf("attachment; filename=\"%s\"; filename*=UTF-8''%s", fallback.String(), encoded.String())
+}
+
+func streamMatchExport(ctx context.Context, w http.ResponseWriter, app *GlobalAppData, cursor *mongo.Cursor, format, filename string) error {
+	defer cursor.Close(ctx)
+
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Now().Add(exportWriteTimeout)); err != nil {
+		log.Printf("Export: Could not extend write deadline: %v", err)
+	}
+
+	w.Header().Set("Content-Type", exportContentTypes[format])
+	w.Header().Set("Content-Disposition", contentDisposition(filename))
+	w.WriteHeader(http.StatusOK)
+
+	// flush pushes buffered rows to the client; finish completes the file
+	var writeRow func(row MatchExportRow) error
+	flush := func() error { return nil }
+	finish := func() error { return nil }
+
+	switch format {
+	case exportFormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(exportCSVHeader); err != nil {
+			return err
+		}
+		writeRow = func(row MatchExportRow) error { return cw.Write(exportCSVRecord(row)) }
+		flush = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+		finish = flush
+	case exportFormatNDJSON:
+		encoder := json.NewEncoder(w)
+		writeRow = func(row MatchExportRow) error { return encoder.Encode(row) }
+	case exportFormatParquet:
+		// Row groups are written out as they fill up; the footer is written on Close
+		pw := parquet.NewGenericWriter[MatchExportRow](w, parquet.MaxRowsPerRowGroup(exportRowGroupSize))
+		writeRow = func(row MatchExportRow) error {
+			_, err := pw.Write([]MatchExportRow{row})
+			return err
+		}
+		finish = pw.Close
+	default:
+		return fmt.Errorf("unsupported export format %q", format)
+	}
+
+	rows := 0
+	for cursor.Next(ctx) {
+		var match PlayerMatchStats
+		if err := cursor.Decode(&match); err != nil {
+			return fmt.Errorf("failed to decode stored match: %w", err)
+		}
+		if err := writeRow(toMatchExportRow(app.staticData, match)); err != nil {
+			return fmt.Errorf("failed to write export row: %w", err)
+		}
+		rows++
+		if rows%exportFlushEvery == 0 {
+			if err := flush(); err != nil {
+				return fmt.Errorf("failed to flush export: %w", err)
+			}
+			_ = rc.Flush()
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed while reading stored matches: %w", err)
+	}
+
+	if err := finish(); err != nil {
+		return fmt.Errorf("failed to finish export: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,687 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var graphQLSchema string
+
+const (
+	graphQLMaxDepth         = 10
+	graphQLMaxMatchCount    = 100                  // Matches a player query can ask for
+	graphQLMaxParallelism   = graphQLMaxMatchCount // Resolvers per request running at once; at least the matches per page so they batch
+	graphQLMaxBodyBytes     = 64 * 1024
+	graphQLMaxPlayerLookups = 5 // Player fields per request, aliases included, since each can fetch a full page of matches
+)
+
+// graphQLRequest is a GraphQL-over-HTTP request body
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// graphQLError carries an APIError's stable code into the GraphQL errors array
+type graphQLError struct {
+	apiErr *APIError
+}
+
+func (e *graphQLError) Error() string {
+	return e.apiErr.Message
+}
+
+func (e *graphQLError) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{"code": e.apiErr.Code, "status": e.apiErr.Status}
+	if e.apiErr.RetryAfter > 0 {
+		extensions["retryAfter"] = e.apiErr.RetryAfter
+	}
+	if len(e.apiErr.Details) > 0 {
+		extensions["details"] = e.apiErr.Details
+	}
+	return extensions
+}
+
+// toGraphQLError maps err like the REST handlers do so internal details never leak
+func toGraphQLError(err error, fallbackMessage string) error {
+	return &graphQLError{apiErr: toAPIError(err, fallbackMessage)}
+}
+
+// graphQLHandler serves /api/graphql over GET (?query=) and POST (JSON body)
+func graphQLHandler(app *GlobalAppData) http.HandlerFunc {
+	schema := graphql.MustParseSchema(graphQLSchema, &graphQLQueryResolver{app: app},
+		graphql.UseStringDescriptions(),
+		graphql.MaxDepth(graphQLMaxDepth),
+		graphql.MaxParallelism(graphQLMaxParallelism),
+	)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req graphQLRequest
+		if r.Method == http.MethodGet {
+			query := r.URL.Query()
+			req.Query = query.Get("query")
+			req.OperationName = query.Get("operationName")
+			if variables := query.Get("variables"); variables != "" {
+				if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+					writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid variables parameter"))
+					return
+				}
+			}
+		} else if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, graphQLMaxBodyBytes)).Decode(&req); err != nil {
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid request body"))
+			return
+		}
+		if strings.TrimSpace(req.Query) == "" {
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Missing GraphQL query"))
+			return
+		}
+
+		if app.staticData == nil {
+			log.Println("Static data not yet loaded, attempting to load now.")
+			if err := populateStaticData(app); err != nil {
+				log.Printf("Error populating static data on demand: %v", err)
+				writeAPIError(w, r, newAPIError(http.StatusServiceUnavailable, ErrCodeUpstreamUnavailable, "Error loading required game data. Please try again shortly."))
+				return
+			}
+		}
+
+		ctx := withMatchDetailsLoader(r.Context(), newMatchDetailsLoader(app))
+		ctx = context.WithValue(ctx, graphQLPlayerLookupsContextKey{}, new(int32))
+		response := schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", cacheControlNever)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding GraphQL response: %v", err)
+		}
+	}
+}
+
+type graphQLPlayerLookupsContextKey struct{}
+
+// takePlayerLookup counts a player field against the request's graphQLMaxPlayerLookups
+func takePlayerLookup(ctx context.Context) error {
+	lookups, ok := ctx.Value(graphQLPlayerLookupsContextKey{}).(*int32)
+	if ok && atomic.AddInt32(lookups, 1) > graphQLMaxPlayerLookups {
+		return &graphQLError{apiErr: newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "A query can look up at most "+strconv.Itoa(graphQLMaxPlayerLookups)+" players")}
+	}
+	return nil
+}
+
+func requireRiotAPIKey(app *GlobalAppData) error {
+	if app.riotAPIKey == "" {
+		log.Println("Error: RIOT_API_KEY is not set.")
+		return &graphQLError{apiErr: newAPIError(http.StatusInternalServerError, ErrCodeServerMisconfigured, "Server configuration error: Riot API Key not set.")}
+	}
+	return nil
+}
+
+// Query
+
+type graphQLQueryResolver struct {
+	app *GlobalAppData
+}
+
+func (q *graphQLQueryResolver) Player(ctx context.Context, args struct {
+	Region   string
+	GameName string
+	TagLine  string
+	Count    int32
+	QueueID  int32
+}) (*playerResolver, error) {
+	if err := takePlayerLookup(ctx); err != nil {
+		return nil, err
+	}
+	gameName, tagLine, region, err := ValidateAndSanitizeInput(args.GameName, args.TagLine, args.Region)
+	if err != nil {
+		return nil, &graphQLError{apiErr: validationAPIError(err, "Invalid input")}
+	}
+	if PreventNoSQLInjection(gameName) != nil || PreventNoSQLInjection(tagLine) != nil {
+		return nil, &graphQLError{apiErr: newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected")}
+	}
+	count, err := ValidateCount(strconv.Itoa(int(args.Count)), defaultMatchCount, graphQLMaxMatchCount)
+	if err != nil {
+		return nil, &graphQLError{apiErr: validationAPIError(err, "Invalid count argument")}
+	}
+	queueID, err := validateQueueIDParam(q.app, strconv.Itoa(int(args.QueueID)))
+	if err != nil {
+		return nil, &graphQLError{apiErr: validationAPIError(err, "Invalid queueId argument")}
+	}
+	if err := requireRiotAPIKey(q.app); err != nil {
+		return nil, err
+	}
+
+	performance, err := fetchAndStoreUserPerformance(q.app, region, gameName, tagLine, count, queueID, 0)
+	if err != nil {
+		log.Printf("GraphQL: Error fetching user performance for %s#%s: %v", gameName, tagLine, err)
+		return nil, toGraphQLError(err, "Error fetching user performance")
+	}
+	return &playerResolver{app: q.app, performance: performance}, nil
+}
+
+func (q *graphQLQueryResolver) Match(args struct {
+	Region  string
+	MatchID string
+}) (*matchResolver, error) {
+	region, matchID, err := ValidateMatchInput(args.Region, args.MatchID)
+	if err != nil {
+		return nil, &graphQLError{apiErr: validationAPIError(err, "Invalid input")}
+	}
+	if PreventNoSQLInjection(matchID) != nil {
+		return nil, &graphQLError{apiErr: newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected")}
+	}
+	if err := requireRiotAPIKey(q.app); err != nil {
+		return nil, err
+	}
+	return &matchResolver{app: q.app, region: region, matchID: matchID}, nil
+}
+
+func (q *graphQLQueryResolver) Champion(args struct {
+	ID  *int32
+	Key *string
+}) *championResolver {
+	sd := q.app.staticData
+	if args.ID != nil {
+		if _, ok := sd.Champions[strconv.Itoa(int(*args.ID))]; ok {
+			return newChampionResolver(sd, int(*args.ID), "")
+		}
+		return nil
+	}
+	if args.Key != nil {
+		for key, champ := range sd.Champions {
+			if strings.EqualFold(champ.ID, *args.Key) {
+				id, _ := strconv.Atoi(key)
+				return newChampionResolver(sd, id, "")
+			}
+		}
+	}
+	return nil
+}
+
+func (q *graphQLQueryResolver) Champions() []*championResolver {
+	sd := q.app.staticData
+	champions := make([]*championResolver, 0, len(sd.Champions))
+	for key := range sd.Champions {
+		id, _ := strconv.Atoi(key)
+		champions = append(champions, newChampionResolver(sd, id, ""))
+	}
+	sort.Slice(champions, func(i, j int) bool { return champions[i].Name() < champions[j].Name() })
+	return champions
+}
+
+func (q *graphQLQueryResolver) Item(args struct{ ID int32 }) *itemResolver {
+	return newItemResolver(q.app.staticData, strconv.Itoa(int(args.ID)))
+}
+
+func (q *graphQLQueryResolver) Items(args struct{ Tag *string }) []*itemResolver {
+	sd := q.app.staticData
+	items := make([]*itemResolver, 0, len(sd.Items))
+	for key, item := range sd.Items {
+		if args.Tag != nil && !containsFold(item.Tags, *args.Tag) {
+			continue
+		}
+		if resolver := newItemResolver(sd, key); resolver != nil {
+			items = append(items, resolver)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].id < items[j].id })
+	return items
+}
+
+func (q *graphQLQueryResolver) StaticDataVersion() string {
+	return q.app.staticData.LatestVersion
+}
+
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Player
+
+type playerResolver struct {
+	app         *GlobalAppData
+	performance *UserPerformance
+
+	summaryOnce sync.Once
+	summary     *RecentGamesSummary
+}
+
+// recentSummary computes the summary on first use, so queries that only want matches skip it
+func (p *playerResolver) recentSummary() *RecentGamesSummary {
+	p.summaryOnce.Do(func() {
+		perf := p.performance
+		p.summary = calculateRecentGamesSummary(p.app.staticData, perf.Matches, perf.PUUID, perf.Region, perf.RiotID)
+	})
+	return p.summary
+}
+
+func (p *playerResolver) Puuid() string  { return p.performance.PUUID }
+func (p *playerResolver) Region() string { return p.performance.Region }
+func (p *playerResolver) RiotID() string { return p.performance.RiotID }
+
+func (p *playerResolver) GameName() string {
+	gameName, _, _ := strings.Cut(p.performance.RiotID, "#")
+	return gameName
+}
+
+func (p *playerResolver) TagLine() string {
+	_, tagLine, _ := strings.Cut(p.performance.RiotID, "#")
+	return tagLine
+}
+
+func (p *playerResolver) RankTier() *string {
+	if p.performance.RankTier == "" {
+		return nil
+	}
+	return &p.performance.RankTier
+}
+
+func (p *playerResolver) UpdatedAt() float64  { return float64(p.performance.UpdatedAt) * 1000 }
+func (p *playerResolver) TotalMatches() int32 { return int32(len(p.performance.Matches)) }
+
+func (p *playerResolver) OverallStats() *overallStatsResolver {
+	return &overallStatsResolver{stats: p.recentSummary().OverallStats}
+}
+
+func (p *playerResolver) RoleStats() []*roleStatsResolver {
+	roles := make([]*roleStatsResolver, 0, len(p.recentSummary().RoleStats))
+	for _, stats := range p.recentSummary().RoleStats {
+		roles = append(roles, &roleStatsResolver{stats: stats})
+	}
+	sort.Slice(roles, func(i, j int) bool {
+		if roles[i].stats.GamesPlayed != roles[j].stats.GamesPlayed {
+			return roles[i].stats.GamesPlayed > roles[j].stats.GamesPlayed
+		}
+		return roles[i].stats.Role < roles[j].stats.Role
+	})
+	return roles
+}
+
+func (p *playerResolver) ChampionStats(args struct{ Limit *int32 }) []*championStatsResolver {
+	champions := make([]*championStatsResolver, 0, len(p.recentSummary().ChampionStats))
+	for _, stats := range p.recentSummary().ChampionStats {
+		champions = append(champions, &championStatsResolver{sd: p.app.staticData, stats: stats})
+	}
+	sort.Slice(champions, func(i, j int) bool {
+		if champions[i].stats.GamesPlayed != champions[j].stats.GamesPlayed {
+			return champions[i].stats.GamesPlayed > champions[j].stats.GamesPlayed
+		}
+		return champions[i].stats.ChampionID < champions[j].stats.ChampionID
+	})
+	if args.Limit != nil && *args.Limit >= 0 && int(*args.Limit) < len(champions) {
+		champions = champions[:*args.Limit]
+	}
+	return champions
+}
+
+func (p *playerResolver) Matches(args struct{ Limit *int32 }) []*matchResolver {
+	matches := p.performance.Matches
+	if args.Limit != nil && *args.Limit >= 0 && int(*args.Limit) < len(matches) {
+		matches = matches[:*args.Limit]
+	}
+	resolvers := make([]*matchResolver, 0, len(matches))
+	for i := range matches {
+		resolvers = append(resolvers, &matchResolver{
+			app:     p.app,
+			region:  p.performance.Region,
+			matchID: matches[i].MatchID,
+			puuid:   p.performance.PUUID,
+			focus:   &matches[i],
+		})
+	}
+	return resolvers
+}
+
+// Stats
+
+type overallStatsResolver struct {
+	stats OverallStats
+}
+
+func (o *overallStatsResolver) Wins() int32                   { return int32(o.stats.Wins) }
+func (o *overallStatsResolver) Losses() int32                 { return int32(o.stats.Losses) }
+func (o *overallStatsResolver) WinRate() float64              { return o.stats.WinRate }
+func (o *overallStatsResolver) AvgKills() float64             { return o.stats.AvgKills }
+func (o *overallStatsResolver) AvgDeaths() float64            { return o.stats.AvgDeaths }
+func (o *overallStatsResolver) AvgAssists() float64           { return o.stats.AvgAssists }
+func (o *overallStatsResolver) KDA() float64                  { return o.stats.OverallKDA }
+func (o *overallStatsResolver) AvgGameDuration() float64      { return o.stats.AvgGameDuration }
+func (o *overallStatsResolver) AvgVisionScore() float64       { return o.stats.AvgVisionScore }
+func (o *overallStatsResolver) AvgCSPerMin() float64          { return o.stats.AvgCSPerMin }
+func (o *overallStatsResolver) AvgGoldPerMin() float64        { return o.stats.AvgGoldPerMin }
+func (o *overallStatsResolver) AvgDamageToChampions() float64 { return o.stats.AvgDamageToChampions }
+func (o *overallStatsResolver) AvgKillParticipation() float64 { return o.stats.AvgKillParticipation }
+
+type roleStatsResolver struct {
+	stats RoleStats
+}
+
+func (r *roleStatsResolver) Role() string                  { return r.stats.Role }
+func (r *roleStatsResolver) GamesPlayed() int32            { return int32(r.stats.GamesPlayed) }
+func (r *roleStatsResolver) Wins() int32                   { return int32(r.stats.Wins) }
+func (r *roleStatsResolver) Losses() int32                 { return int32(r.stats.Losses) }
+func (r *roleStatsResolver) WinRate() float64              { return r.stats.WinRate }
+func (r *roleStatsResolver) AvgKills() float64             { return r.stats.AvgKills }
+func (r *roleStatsResolver) AvgDeaths() float64            { return r.stats.AvgDeaths }
+func (r *roleStatsResolver) AvgAssists() float64           { return r.stats.AvgAssists }
+func (r *roleStatsResolver) KDA() float64                  { return r.stats.RoleKDA }
+func (r *roleStatsResolver) AvgVisionScore() float64       { return r.stats.AvgVisionScore }
+func (r *roleStatsResolver) AvgCSPerMin() float64          { return r.stats.AvgCSPerMin }
+func (r *roleStatsResolver) AvgGoldPerMin() float64        { return r.stats.AvgGoldPerMin }
+func (r *roleStatsResolver) AvgDamageToChampions() float64 { return r.stats.AvgDamageToChampions }
+func (r *roleStatsResolver) AvgKillParticipation() float64 { return r.stats.AvgKillParticipation }
+func (r *roleStatsResolver) AvgPerformanceScore() float64  { return r.stats.AvgPerformanceScore }
+func (r *roleStatsResolver) MvpCount() int32               { return int32(r.stats.MVPCount) }
+func (r *roleStatsResolver) AceCount() int32               { return int32(r.stats.ACECount) }
+
+type championStatsResolver struct {
+	sd    *StaticData
+	stats ChampionStats
+}
+
+func (c *championStatsResolver) Champion() *championResolver {
+	return newChampionResolver(c.sd, c.stats.ChampionID, c.stats.ChampionName)
+}
+
+func (c *championStatsResolver) GamesPlayed() int32            { return int32(c.stats.GamesPlayed) }
+func (c *championStatsResolver) Wins() int32                   { return int32(c.stats.Wins) }
+func (c *championStatsResolver) Losses() int32                 { return int32(c.stats.Losses) }
+func (c *championStatsResolver) WinRate() float64              { return c.stats.WinRate }
+func (c *championStatsResolver) AvgKills() float64             { return c.stats.AvgKills }
+func (c *championStatsResolver) AvgDeaths() float64            { return c.stats.AvgDeaths }
+func (c *championStatsResolver) AvgAssists() float64           { return c.stats.AvgAssists }
+func (c *championStatsResolver) KDA() float64                  { return c.stats.ChampionKDA }
+func (c *championStatsResolver) BestKDA() float64              { return c.stats.BestKDA }
+func (c *championStatsResolver) WorstKDA() float64             { return c.stats.WorstKDA }
+func (c *championStatsResolver) AvgVisionScore() float64       { return c.stats.AvgVisionScore }
+func (c *championStatsResolver) AvgCSPerMin() float64          { return c.stats.AvgCSPerMin }
+func (c *championStatsResolver) AvgGoldPerMin() float64        { return c.stats.AvgGoldPerMin }
+func (c *championStatsResolver) AvgDamageToChampions() float64 { return c.stats.AvgDamageToChampions }
+func (c *championStatsResolver) AvgKillParticipation() float64 { return c.stats.AvgKillParticipation }
+func (c *championStatsResolver) AvgPerformanceScore() float64  { return c.stats.AvgPerformanceScore }
+func (c *championStatsResolver) MvpCount() int32               { return int32(c.stats.MVPCount) }
+func (c *championStatsResolver) AceCount() int32               { return int32(c.stats.ACECount) }
+func (c *championStatsResolver) LastPlayed() float64           { return float64(c.stats.LastPlayed) }
+
+// Match
+
+// matchResolver answers from the player's own match row when it can and loads the full match
+// through the request's matchDetailsLoader otherwise
+type matchResolver struct {
+	app     *GlobalAppData
+	region  string
+	matchID string
+	puuid   string            // Set when reached through Player.matches
+	focus   *PlayerMatchStats // The player's row, when reached through Player.matches
+
+	loadOnce   sync.Once
+	scoreboard *MatchScoreboard
+	loadErr    error
+}
+
+func (m *matchResolver) load(ctx context.Context) (*MatchScoreboard, error) {
+	m.loadOnce.Do(func() {
+		match, err := matchDetailsLoaderFrom(ctx, m.app).Load(ctx, m.region, m.matchID)
+		switch {
+		case err != nil:
+			log.Printf("GraphQL: Error fetching match details for %s: %v", m.matchID, err)
+			m.loadErr = toGraphQLError(err, "Error fetching match details")
+		case match == nil:
+			m.loadErr = &graphQLError{apiErr: newAPIError(http.StatusNotFound, ErrCodeMatchNotFound, "Match not found")}
+		default:
+			m.scoreboard = buildMatchScoreboard(m.app, match)
+		}
+	})
+	return m.scoreboard, m.loadErr
+}
+
+func (m *matchResolver) MatchID() string { return m.matchID }
+func (m *matchResolver) Region() string  { return m.region }
+
+func (m *matchResolver) GameMode(ctx context.Context) (string, error) {
+	if m.focus != nil {
+		return m.focus.GameMode, nil
+	}
+	scoreboard, err := m.load(ctx)
+	if err != nil {
+		return "", err
+	}
+	return scoreboard.GameMode, nil
+}
+
+func (m *matchResolver) GameVersion(ctx context.Context) (string, error) {
+	scoreboard, err := m.load(ctx)
+	if err != nil {
+		return "", err
+	}
+	return scoreboard.GameVersion, nil
+}
+
+func (m *matchResolver) queueID(ctx context.Context) (int, error) {
+	if m.focus != nil {
+		return m.focus.QueueID, nil
+	}
+	scoreboard, err := m.load(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return scoreboard.QueueID, nil
+}
+
+func (m *matchResolver) QueueID(ctx context.Context) (int32, error) {
+	queueID, err := m.queueID(ctx)
+	return int32(queueID), err
+}
+
+func (m *matchResolver) QueueName(ctx context.Context) (string, error) {
+	queueID, err := m.queueID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return resolveQueue(m.app.staticData, queueID).Name, nil
+}
+
+func (m *matchResolver) GameCreation(ctx context.Context) (float64, error) {
+	if m.focus != nil {
+		return float64(m.focus.GameCreation), nil
+	}
+	scoreboard, err := m.load(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return float64(scoreboard.GameCreation), nil
+}
+
+func (m *matchResolver) GameDuration(ctx context.Context) (int32, error) {
+	if m.focus != nil {
+		return int32(m.focus.GameDuration), nil
+	}
+	scoreboard, err := m.load(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return int32(scoreboard.GameDuration), nil
+}
+
+func (m *matchResolver) Player(ctx context.Context) (*participantResolver, error) {
+	if m.puuid == "" {
+		return nil, nil
+	}
+	participants, err := m.Participants(ctx, struct{ TeamID *int32 }{})
+	if err != nil {
+		return nil, err
+	}
+	for _, participant := range participants {
+		if participant.row.PUUID == m.puuid {
+			return participant, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *matchResolver) Participants(ctx context.Context, args struct{ TeamID *int32 }) ([]*participantResolver, error) {
+	scoreboard, err := m.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var participants []*participantResolver
+	for _, team := range scoreboard.Teams {
+		if args.TeamID != nil && team.TeamID != int(*args.TeamID) {
+			continue
+		}
+		for i := range team.Participants {
+			participants = append(participants, &participantResolver{sd: m.app.staticData, row: &team.Participants[i]})
+		}
+	}
+	return participants, nil
+}
+
+// Participant
+
+type participantResolver struct {
+	sd  *StaticData
+	row *ScoreboardParticipant
+}
+
+func (p *participantResolver) Puuid() string          { return p.row.PUUID }
+func (p *participantResolver) RiotIDGameName() string { return p.row.RiotIDGameName }
+func (p *participantResolver) RiotIDTagline() string  { return p.row.RiotIDTagline }
+
+func (p *participantResolver) Champion() *championResolver {
+	return newChampionResolver(p.sd, p.row.ChampionID, p.row.ChampionName)
+}
+
+func (p *participantResolver) TeamID() int32              { return int32(p.row.TeamID) }
+func (p *participantResolver) TeamPosition() string       { return p.row.TeamPosition }
+func (p *participantResolver) Role() string               { return normalizeRole(p.row.TeamPosition, p.row.GameMode) }
+func (p *participantResolver) Win() bool                  { return p.row.Win }
+func (p *participantResolver) Kills() int32               { return int32(p.row.Kills) }
+func (p *participantResolver) Deaths() int32              { return int32(p.row.Deaths) }
+func (p *participantResolver) Assists() int32             { return int32(p.row.Assists) }
+func (p *participantResolver) KDA() float64               { return p.row.KDA }
+func (p *participantResolver) KillParticipation() float64 { return p.row.KillParticipation }
+func (p *participantResolver) TotalMinionsKilled() int32  { return int32(p.row.TotalMinionsKilled) }
+func (p *participantResolver) VisionScore() int32         { return int32(p.row.VisionScore) }
+func (p *participantResolver) GoldEarned() int32          { return int32(p.row.GoldEarned) }
+func (p *participantResolver) ChampLevel() int32          { return int32(p.row.ChampLevel) }
+func (p *participantResolver) DamageToChampions() int32   { return int32(p.row.DamageToChampions) }
+func (p *participantResolver) DamageTaken() int32         { return int32(p.row.TotalDamageTaken) }
+func (p *participantResolver) DamageShare() float64       { return p.row.DamageShare }
+func (p *participantResolver) GoldShare() float64         { return p.row.GoldShare }
+func (p *participantResolver) PerformanceScore() float64  { return p.row.PerformanceScore }
+
+func (p *participantResolver) PerformanceGrade() *string {
+	if p.row.PerformanceGrade == "" {
+		return nil
+	}
+	return &p.row.PerformanceGrade
+}
+
+func (p *participantResolver) Items() []*itemResolver {
+	items := make([]*itemResolver, 0, len(p.row.Items))
+	for _, itemID := range p.row.Items {
+		if itemID == 0 {
+			continue
+		}
+		if item := newItemResolver(p.sd, strconv.Itoa(itemID)); item != nil {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Static data
+
+type championResolver struct {
+	id   int
+	data ChampionData
+	ref  StaticRef
+}
+
+// newChampionResolver resolves a champion ID, falling back to fallbackName for champions newer
+// than the loaded static data
+func newChampionResolver(sd *StaticData, championID int, fallbackName string) *championResolver {
+	champion := &championResolver{id: championID, ref: resolveChampionRef(sd, championID)}
+	if sd != nil {
+		champion.data = sd.Champions[strconv.Itoa(championID)]
+	}
+	if champion.ref.Name == "" {
+		champion.ref.Name = fallbackName
+	}
+	return champion
+}
+
+func (c *championResolver) ID() int32       { return int32(c.id) }
+func (c *championResolver) Key() string     { return c.data.ID }
+func (c *championResolver) Name() string    { return c.ref.Name }
+func (c *championResolver) Title() string   { return c.data.Title }
+func (c *championResolver) Image() string   { return c.ref.Image }
+func (c *championResolver) Partype() string { return c.data.Partype }
+
+type itemResolver struct {
+	sd   *StaticData
+	id   int
+	data ItemData
+}
+
+// newItemResolver returns nil for items missing from static data
+func newItemResolver(sd *StaticData, itemKey string) *itemResolver {
+	if sd == nil {
+		return nil
+	}
+	item, ok := sd.Items[itemKey]
+	if !ok {
+		return nil
+	}
+	id, err := strconv.Atoi(itemKey)
+	if err != nil {
+		return nil
+	}
+	return &itemResolver{sd: sd, id: id, data: item}
+}
+
+func (i *itemResolver) ID() int32           { return int32(i.id) }
+func (i *itemResolver) Name() string        { return i.data.Name }
+func (i *itemResolver) Plaintext() string   { return i.data.Plaintext }
+func (i *itemResolver) Description() string { return i.data.Description }
+func (i *itemResolver) Image() string       { return resolveItemRef(i.sd, i.id).Image }
+func (i *itemResolver) GoldTotal() int32    { return int32(i.data.Gold.Total) }
+func (i *itemResolver) GoldBase() int32     { return int32(i.data.Gold.Base) }
+func (i *itemResolver) GoldSell() int32     { return int32(i.data.Gold.Sell) }
+func (i *itemResolver) Purchasable() bool   { return i.data.Gold.Purchasable }
+
+func (i *itemResolver) Tags() []string {
+	if i.data.Tags == nil {
+		return []string{}
+	}
+	return i.data.Tags
+}
+
+func (i *itemResolver) From() []*itemResolver { return i.related(i.data.From) }
+func (i *itemResolver) Into() []*itemResolver { return i.related(i.data.Into) }
+
+func (i *itemResolver) related(keys []string) []*itemResolver {
+	items := make([]*itemResolver, 0, len(keys))
+	for _, key := range keys {
+		if item := newItemResolver(i.sd, key); item != nil {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -26,19 +28,19 @@ func getPlayerPerformanceHandler(app *GlobalAppData) http.HandlerFunc {
 		validatedGameName, validatedTagLine, validatedRegion, err := ValidateAndSanitizeInput(gameName, tagLine, region)
 		if err != nil {
 			log.Printf("Input validation error: %v", err)
-			http.Error(w, fmt.Sprintf("Invalid input: %v", err), http.StatusBadRequest)
+			writeValidationError(w, r, err, "Invalid input")
 			return
 		}
 
 		// Additional NoSQL injection prevention
 		if err := PreventNoSQLInjection(validatedGameName); err != nil {
 			log.Printf("Potential NoSQL injection attempt in gameName: %s", validatedGameName)
-			http.Error(w, "Invalid input detected", http.StatusBadRequest)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
 			return
 		}
 		if err := PreventNoSQLInjection(validatedTagLine); err != nil {
 			log.Printf("Potential NoSQL injection attempt in tagLine: %s", validatedTagLine)
-			http.Error(w, "Invalid input detected", http.StatusBadRequest)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
 			return
 		}
 
@@ -46,15 +48,15 @@ func getPlayerPerformanceHandler(app *GlobalAppData) http.HandlerFunc {
 		count, err := ValidateCount(countStr, defaultMatchCount, 100)
 		if err != nil {
 			log.Printf("Count validation error: %v", err)
-			http.Error(w, fmt.Sprintf("Invalid count parameter: %v", err), http.StatusBadRequest)
+			writeValidationError(w, r, err, "Invalid count parameter")
 			return
 		}
 
 		// Validate queueID parameter
-		queueID, err := ValidateQueueID(queueIDStr, defaultQueueID)
+		queueID, err := validateQueueIDParam(app, queueIDStr)
 		if err != nil {
 			log.Printf("QueueID validation error: %v", err)
-			http.Error(w, fmt.Sprintf("Invalid queueId parameter: %v", err), http.StatusBadRequest)
+			writeValidationError(w, r, err, "Invalid queueId parameter")
 			return
 		}
 
@@ -62,7 +64,7 @@ func getPlayerPerformanceHandler(app *GlobalAppData) http.HandlerFunc {
 
 		if app.riotAPIKey == "" {
 			log.Println("Error: RIOT_API_KEY is not set.")
-			http.Error(w, "Server configuration error: Riot API Key not set.", http.StatusInternalServerError)
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeServerMisconfigured, "Server configuration error: Riot API Key not set."))
 			return
 		}
 
@@ -71,7 +73,7 @@ func getPlayerPerformanceHandler(app *GlobalAppData) http.HandlerFunc {
 			err := populateStaticData(app)
 			if err != nil {
 				log.Printf("Error populating static data on demand: %v", err)
-				http.Error(w, "Error loading required game data. Please try again shortly.", http.StatusInternalServerError)
+				writeAPIError(w, r, newAPIError(http.StatusServiceUnavailable, ErrCodeUpstreamUnavailable, "Error loading required game data. Please try again shortly."))
 				return
 			}
 		}
@@ -79,14 +81,19 @@ func getPlayerPerformanceHandler(app *GlobalAppData) http.HandlerFunc {
 		performance, err := fetchAndStoreUserPerformance(app, validatedRegion, validatedGameName, validatedTagLine, count, queueID, 0)
 		if err != nil {
 			log.Printf("Error fetching user performance for %s#%s: %v", validatedGameName, validatedTagLine, err)
-			http.Error(w, fmt.Sprintf("Error fetching user performance: %v", err), http.StatusInternalServerError)
+			writeError(w, r, err, "Error fetching user performance")
+			return
+		}
+
+		etag := playerETag(latestMatchID(performance.Matches), performance.UpdatedAt, "matches", strconv.Itoa(count), strconv.Itoa(queueID))
+		if notModified(w, r, etag, cacheControlPlayer) {
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(performance); err != nil {
 			log.Printf("Error encoding response for %s#%s: %v", validatedGameName, validatedTagLine, err)
-			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeInternal, "Failed to encode response"))
 		}
 	}
 }
@@ -98,29 +105,29 @@ func getStaticDataHandler(app *GlobalAppData) http.HandlerFunc {
 			err := populateStaticData(app)
 			if err != nil {
 				log.Printf("Error populating static data on demand for /static-data: %v", err)
-				http.Error(w, "Static data is not available at the moment, please try again later.", http.StatusServiceUnavailable)
+				writeAPIError(w, r, newAPIError(http.StatusServiceUnavailable, ErrCodeUpstreamUnavailable, "Static data is not available at the moment, please try again later."))
 				return
 			}
 		}
 
-		response := struct {
-			Champions      map[string]ChampionData      `json:"champions"`
-			Items          map[string]ItemData          `json:"items"`
-			Runes          map[int]RuneInfo             `json:"runes"`
-			SummonerSpells map[string]SummonerSpellData `json:"summonerSpells"`
-			LatestVersion  string                       `json:"latestVersion"`
-		}{
+		if notModified(w, r, staticDataETag(app.staticData, "static-data"), cacheControlStatic) {
+			return
+		}
+
+		response := StaticDataResponse{
 			Champions:      app.staticData.Champions,
 			Items:          app.staticData.Items,
 			Runes:          app.staticData.Runes,
 			SummonerSpells: app.staticData.SummonerSpells,
+			Queues:         app.staticData.Queues,
+			Augments:       app.staticData.Augments,
 			LatestVersion:  app.staticData.LatestVersion,
 		}
 
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(response); err != nil {
 			log.Printf("Error encoding static data response: %v", err)
-			http.Error(w, "Failed to encode static data response", http.StatusInternalServerError)
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeInternal, "Failed to encode static data response"))
 		}
 	}
 }
@@ -141,14 +148,14 @@ func getMatchDetailsHandler(app *GlobalAppData) http.HandlerFunc {
 		validatedRegion, validatedMatchId, err := ValidateMatchInput(region, matchId)
 		if err != nil {
 			log.Printf("Input validation error: %v", err)
-			http.Error(w, fmt.Sprintf("Invalid input: %v", err), http.StatusBadRequest)
+			writeValidationError(w, r, err, "Invalid input")
 			return
 		}
 
 		// Additional NoSQL injection prevention
 		if err := PreventNoSQLInjection(validatedMatchId); err != nil {
 			log.Printf("Potential NoSQL injection attempt in matchId: %s", validatedMatchId)
-			http.Error(w, "Invalid input detected", http.StatusBadRequest)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
 			return
 		}
 
@@ -156,18 +163,23 @@ func getMatchDetailsHandler(app *GlobalAppData) http.HandlerFunc {
 
 		if app.riotAPIKey == "" {
 			log.Println("Error: RIOT_API_KEY is not set.")
-			http.Error(w, "Server configuration error: Riot API Key not set.", http.StatusInternalServerError)
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeServerMisconfigured, "Server configuration error: Riot API Key not set."))
+			return
+		}
+
+		// A finished match never changes, so a cached copy can be confirmed without fetching it
+		if notModified(w, r, strongETag("match", validatedRegion, validatedMatchId), cacheControlMatch) {
 			return
 		}
 
 		match, err := getMatchDetails(app, validatedRegion, validatedMatchId)
 		if err != nil {
 			log.Printf("Error fetching match details for %s: %v", validatedMatchId, err)
-			http.Error(w, "Error fetching match details", http.StatusInternalServerError)
+			writeError(w, r, err, "Error fetching match details")
 			return
 		}
 		if match == nil {
-			http.Error(w, "Match not found", http.StatusNotFound)
+			writeAPIError(w, r, newAPIError(http.StatusNotFound, ErrCodeMatchNotFound, "Match not found"))
 			return
 		}
 		w.Header().Set("Content-Type", "application/json")
@@ -259,7 +271,7 @@ func getPopularItemsHandler(app *GlobalAppData) http.HandlerFunc {
 		itemIDs, dbErr := fetchTopPopularItemIDsFromDB(app, topNPopularItems)
 		if dbErr != nil {
 			log.Printf("Error fetching popular items from DB: %v", dbErr)
-			http.Error(w, "Failed to fetch popular items.", http.StatusInternalServerError)
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeInternal, "Failed to fetch popular items."))
 			return
 		}
 
@@ -303,19 +315,19 @@ func getRecentGamesSummaryHandler(app *GlobalAppData) http.HandlerFunc {
 		validatedGameName, validatedTagLine, validatedRegion, err := ValidateAndSanitizeInput(gameName, tagLine, region)
 		if err != nil {
 			log.Printf("Input validation error: %v", err)
-			http.Error(w, fmt.Sprintf("Invalid input: %v", err), http.StatusBadRequest)
+			writeValidationError(w, r, err, "Invalid input")
 			return
 		}
 
 		// Additional NoSQL injection prevention
 		if err := PreventNoSQLInjection(validatedGameName); err != nil {
 			log.Printf("Potential NoSQL injection attempt in gameName: %s", validatedGameName)
-			http.Error(w, "Invalid input detected", http.StatusBadRequest)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
 			return
 		}
 		if err := PreventNoSQLInjection(validatedTagLine); err != nil {
 			log.Printf("Potential NoSQL injection attempt in tagLine: %s", validatedTagLine)
-			http.Error(w, "Invalid input detected", http.StatusBadRequest)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
 			return
 		}
 
@@ -323,15 +335,15 @@ func getRecentGamesSummaryHandler(app *GlobalAppData) http.HandlerFunc {
 		count, err := ValidateCount(countStr, defaultMatchCount, 100)
 		if err != nil {
 			log.Printf("Count validation error: %v", err)
-			http.Error(w, fmt.Sprintf("Invalid count parameter: %v", err), http.StatusBadRequest)
+			writeValidationError(w, r, err, "Invalid count parameter")
 			return
 		}
 
 		// Validate queueID parameter
-		queueID, err := ValidateQueueID(queueIDStr, defaultQueueID)
+		queueID, err := validateQueueIDParam(app, queueIDStr)
 		if err != nil {
 			log.Printf("QueueID validation error: %v", err)
-			http.Error(w, fmt.Sprintf("Invalid queueId parameter: %v", err), http.StatusBadRequest)
+			writeValidationError(w, r, err, "Invalid queueId parameter")
 			return
 		}
 
@@ -339,7 +351,7 @@ func getRecentGamesSummaryHandler(app *GlobalAppData) http.HandlerFunc {
 
 		if app.riotAPIKey == "" {
 			log.Println("Error: RIOT_API_KEY is not set.")
-			http.Error(w, "Server configuration error: Riot API Key not set.", http.StatusInternalServerError)
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeServerMisconfigured, "Server configuration error: Riot API Key not set."))
 			return
 		}
 
@@ -348,7 +360,7 @@ func getRecentGamesSummaryHandler(app *GlobalAppData) http.HandlerFunc {
 			err := populateStaticData(app)
 			if err != nil {
 				log.Printf("Error populating static data on demand: %v", err)
-				http.Error(w, "Error loading required game data. Please try again shortly.", http.StatusInternalServerError)
+				writeAPIError(w, r, newAPIError(http.StatusServiceUnavailable, ErrCodeUpstreamUnavailable, "Error loading required game data. Please try again shortly."))
 				return
 			}
 		}
@@ -356,19 +368,37 @@ func getRecentGamesSummaryHandler(app *GlobalAppData) http.HandlerFunc {
 		summaryData, err := fetchRecentGamesSummary(app, validatedRegion, validatedGameName, validatedTagLine, count, queueID)
 		if err != nil {
 			log.Printf("Error fetching recent games summary for %s#%s: %v", validatedGameName, validatedTagLine, err)
-			http.Error(w, fmt.Sprintf("Error fetching recent games summary: %v", err), http.StatusInternalServerError)
+			writeError(w, r, err, "Error fetching recent games summary")
+			return
+		}
+
+		etag := playerETag(latestMatchID(summaryData.RecentMatches), summaryData.LastUpdated, "summary", strconv.Itoa(count), strconv.Itoa(queueID), app.staticData.LatestVersion)
+		if notModified(w, r, etag, cacheControlPlayer) {
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(summaryData); err != nil {
 			log.Printf("Error encoding response for %s#%s: %v", validatedGameName, validatedTagLine, err)
-			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeInternal, "Failed to encode response"))
 		}
 	}
 }
 
 func getPlayerDashboardHandler(app *GlobalAppData) http.HandlerFunc {
+	return playerDashboardHandler(app, "v1", func(dashboard *PaginatedDashboardResponse) interface{} { return dashboard })
+}
+
+// getPlayerDashboardV2Handler serves the dashboard with match IDs resolved against static data
+func getPlayerDashboardV2Handler(app *GlobalAppData) http.HandlerFunc {
+	return playerDashboardHandler(app, apiVersionV2, func(dashboard *PaginatedDashboardResponse) interface{} {
+		return toDashboardResponseV2(app.staticData, dashboard)
+	})
+}
+
+// playerDashboardHandler validates the request and builds the dashboard; shape picks the
+// response representation for apiVersion
+func playerDashboardHandler(app *GlobalAppData, apiVersion string, shape func(*PaginatedDashboardResponse) interface{}) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		region := chi.URLParam(r, "region")
 		gameName := chi.URLParam(r, "gameName")
@@ -382,19 +412,19 @@ func getPlayerDashboardHandler(app *GlobalAppData) http.HandlerFunc {
 		validatedGameName, validatedTagLine, validatedRegion, err := ValidateAndSanitizeInput(gameName, tagLine, region)
 		if err != nil {
 			log.Printf("Input validation error: %v", err)
-			http.Error(w, fmt.Sprintf("Invalid input: %v", err), http.StatusBadRequest)
+			writeValidationError(w, r, err, "Invalid input")
 			return
 		}
 
 		// NoSQL injection prevention
 		if err := PreventNoSQLInjection(validatedGameName); err != nil {
 			log.Printf("Potential NoSQL injection attempt in gameName: %s", validatedGameName)
-			http.Error(w, "Invalid input detected", http.StatusBadRequest)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
 			return
 		}
 		if err := PreventNoSQLInjection(validatedTagLine); err != nil {
 			log.Printf("Potential NoSQL injection attempt in tagLine: %s", validatedTagLine)
-			http.Error(w, "Invalid input detected", http.StatusBadRequest)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
 			return
 		}
 
@@ -402,21 +432,21 @@ func getPlayerDashboardHandler(app *GlobalAppData) http.HandlerFunc {
 		count, err := ValidateCount(countStr, defaultMatchCount, 100)
 		if err != nil {
 			log.Printf("Count validation error: %v", err)
-			http.Error(w, fmt.Sprintf("Invalid count parameter: %v", err), http.StatusBadRequest)
+			writeValidationError(w, r, err, "Invalid count parameter")
 			return
 		}
 
-		queueID, err := ValidateQueueID(queueIDStr, defaultQueueID)
+		queueID, err := validateQueueIDParam(app, queueIDStr)
 		if err != nil {
 			log.Printf("QueueID validation error: %v", err)
-			http.Error(w, fmt.Sprintf("Invalid queueId parameter: %v", err), http.StatusBadRequest)
+			writeValidationError(w, r, err, "Invalid queueId parameter")
 			return
 		}
 
 		offset, err := ValidateOffset(offsetStr, 0)
 		if err != nil {
 			log.Printf("Offset validation error: %v", err)
-			http.Error(w, fmt.Sprintf("Invalid offset parameter: %v", err), http.StatusBadRequest)
+			writeValidationError(w, r, err, "Invalid offset parameter")
 			return
 		}
 
@@ -424,7 +454,7 @@ func getPlayerDashboardHandler(app *GlobalAppData) http.HandlerFunc {
 
 		if app.riotAPIKey == "" {
 			log.Println("Error: RIOT_API_KEY is not set.")
-			http.Error(w, "Server configuration error: Riot API Key not set.", http.StatusInternalServerError)
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeServerMisconfigured, "Server configuration error: Riot API Key not set."))
 			return
 		}
 
@@ -433,57 +463,834 @@ func getPlayerDashboardHandler(app *GlobalAppData) http.HandlerFunc {
 			err := populateStaticData(app)
 			if err != nil {
 				log.Printf("Error populating static data on demand: %v", err)
-				http.Error(w, "Error loading required game data. Please try again shortly.", http.StatusInternalServerError)
+				writeAPIError(w, r, newAPIError(http.StatusServiceUnavailable, ErrCodeUpstreamUnavailable, "Error loading required game data. Please try again shortly."))
 				return
 			}
 		}
 
-		// Fetch user performance
-		userPerformance, err := fetchAndStoreUserPerformance(app, validatedRegion, validatedGameName, validatedTagLine, count, queueID, offset)
+		dashboardData, err := buildPlayerDashboard(r.Context(), app, validatedRegion, validatedGameName, validatedTagLine, count, queueID, offset)
 		if err != nil {
 			log.Printf("Error fetching user performance for %s#%s: %v", validatedGameName, validatedTagLine, err)
-			http.Error(w, fmt.Sprintf("Error fetching user performance: %v", err), http.StatusInternalServerError)
+			writeError(w, r, err, "Error fetching user performance")
+			return
+		}
+
+		etag := playerETag(dashboardData.LatestMatchID, dashboardData.UpdatedAt, "dashboard", apiVersion, strconv.Itoa(count), strconv.Itoa(queueID), strconv.Itoa(offset), app.staticData.LatestVersion)
+		if notModified(w, r, etag, cacheControlPlayer) {
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(shape(dashboardData)); err != nil {
+			log.Printf("Error encoding dashboard response for %s#%s: %v", validatedGameName, validatedTagLine, err)
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeInternal, "Failed to encode response"))
+		}
+	}
+}
+
+// buildPlayerDashboard fetches a page of matches and assembles the dashboard response;
+// the first page also carries the summary and session analysis
+func buildPlayerDashboard(ctx context.Context, app *GlobalAppData, region, gameName, tagLine string, count, queueID, offset int) (*PaginatedDashboardResponse, error) {
+	userPerformance, err := fetchAndStoreUserPerformance(app, region, gameName, tagLine, count, queueID, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	// Calculate incremental stats for the returned matches
+	incrementalStats := calculateIncrementalStats(userPerformance.Matches)
+
+	// Prepare pagination info
+	hasMore := len(userPerformance.Matches) == count
+	pagination := PaginationInfo{
+		Offset:  offset,
+		Limit:   count,
+		Total:   -1, // We don't know the total
+		HasMore: hasMore,
+	}
+
+	if offset == 0 {
+		// First page: include full summary
+		summary := calculateRecentGamesSummary(app.staticData, userPerformance.Matches, userPerformance.PUUID, userPerformance.Region, userPerformance.RiotID)
+		annotatePercentiles(ctx, app, summary, userPerformance.RankTier)
+		return &PaginatedDashboardResponse{
+			Summary:          summary,
+			Matches:          userPerformance.Matches,
+			Pagination:       pagination,
+			IncrementalStats: incrementalStats,
+			Sessions:         analyzeSessions(userPerformance.Matches),
+			LatestMatchID:    latestMatchID(userPerformance.Matches),
+			UpdatedAt:        userPerformance.UpdatedAt,
+		}, nil
+	}
+
+	// Subsequent pages: no summary, just matches and incremental stats
+	return &PaginatedDashboardResponse{
+		Summary:          nil,
+		Matches:          userPerformance.Matches,
+		Pagination:       pagination,
+		IncrementalStats: incrementalStats,
+		LatestMatchID:    latestMatchID(userPerformance.Matches),
+		UpdatedAt:        userPerformance.UpdatedAt,
+	}, nil
+}
+
+func getTrackedPlayersHandler(app *GlobalAppData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		players, err := listTrackedPlayers(r.Context(), app)
+		if err != nil {
+			log.Printf("Error listing tracked players: %v", err)
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeInternal, "Failed to list tracked players"))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(players); err != nil {
+			log.Printf("Error encoding tracked players response: %v", err)
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeInternal, "Failed to encode response"))
+		}
+	}
+}
+
+func addTrackedPlayerHandler(app *GlobalAppData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req TrackPlayerRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid request body"))
+			return
+		}
+
+		// Validate and sanitize input parameters
+		validatedGameName, validatedTagLine, validatedRegion, err := ValidateAndSanitizeInput(req.GameName, req.TagLine, req.Region)
+		if err != nil {
+			log.Printf("Input validation error: %v", err)
+			writeValidationError(w, r, err, "Invalid input")
+			return
+		}
+
+		// Additional NoSQL injection prevention
+		if err := PreventNoSQLInjection(validatedGameName); err != nil {
+			log.Printf("Potential NoSQL injection attempt in gameName: %s", validatedGameName)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
+			return
+		}
+		if err := PreventNoSQLInjection(validatedTagLine); err != nil {
+			log.Printf("Potential NoSQL injection attempt in tagLine: %s", validatedTagLine)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
+			return
+		}
+
+		log.Printf("Handler: Received track player request for %s#%s in region %s", validatedGameName, validatedTagLine, validatedRegion)
+
+		tracked, err := addTrackedPlayer(r.Context(), app, validatedRegion, validatedGameName, validatedTagLine)
+		if err != nil {
+			log.Printf("Error tracking player %s#%s: %v", validatedGameName, validatedTagLine, err)
+			writeError(w, r, err, "Error tracking player")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(tracked); err != nil {
+			log.Printf("Error encoding tracked player response: %v", err)
+		}
+	}
+}
+
+func removeTrackedPlayerHandler(app *GlobalAppData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		region := chi.URLParam(r, "region")
+		gameName := chi.URLParam(r, "gameName")
+		tagLine := chi.URLParam(r, "tagLine")
+
+		// Validate and sanitize input parameters
+		validatedGameName, validatedTagLine, validatedRegion, err := ValidateAndSanitizeInput(gameName, tagLine, region)
+		if err != nil {
+			log.Printf("Input validation error: %v", err)
+			writeValidationError(w, r, err, "Invalid input")
+			return
+		}
+
+		// Additional NoSQL injection prevention
+		if err := PreventNoSQLInjection(validatedGameName); err != nil {
+			log.Printf("Potential NoSQL injection attempt in gameName: %s", validatedGameName)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
+			return
+		}
+		if err := PreventNoSQLInjection(validatedTagLine); err != nil {
+			log.Printf("Potential NoSQL injection attempt in tagLine: %s", validatedTagLine)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
+			return
+		}
+
+		removed, err := removeTrackedPlayer(r.Context(), app, validatedRegion, validatedGameName, validatedTagLine)
+		if err != nil {
+			log.Printf("Error untracking player %s#%s: %v", validatedGameName, validatedTagLine, err)
+			writeError(w, r, err, "Error untracking player")
+			return
+		}
+		if !removed {
+			writeAPIError(w, r, newAPIError(http.StatusNotFound, ErrCodeNotFound, "Player is not tracked"))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func createFetchJobHandler(app *GlobalAppData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req FetchJobRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid request body"))
+			return
+		}
+
+		// Validate and sanitize input parameters
+		validatedGameName, validatedTagLine, validatedRegion, err := ValidateAndSanitizeInput(req.GameName, req.TagLine, req.Region)
+		if err != nil {
+			log.Printf("Input validation error: %v", err)
+			writeValidationError(w, r, err, "Invalid input")
+			return
+		}
+
+		// Additional NoSQL injection prevention
+		if err := PreventNoSQLInjection(validatedGameName); err != nil {
+			log.Printf("Potential NoSQL injection attempt in gameName: %s", validatedGameName)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
+			return
+		}
+		if err := PreventNoSQLInjection(validatedTagLine); err != nil {
+			log.Printf("Potential NoSQL injection attempt in tagLine: %s", validatedTagLine)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
+			return
+		}
+
+		if req.Count == 0 {
+			req.Count = defaultMatchCount
+		}
+		if req.Count < 0 || req.Count > 100 {
+			writeValidationError(w, r, ValidationError{Field: "count", Message: "count must be between 1 and 100"}, "Invalid count parameter")
+			return
+		}
+		queueID, err := validateQueueIDParam(app, strconv.Itoa(req.QueueID))
+		if err != nil {
+			log.Printf("QueueID validation error: %v", err)
+			writeValidationError(w, r, err, "Invalid queueId parameter")
 			return
 		}
+		req.QueueID = queueID
 
-		// Calculate incremental stats for the returned matches
-		incrementalStats := calculateIncrementalStats(userPerformance.Matches)
+		req.GameName = validatedGameName
+		req.TagLine = validatedTagLine
+		req.Region = validatedRegion
+
+		job, err := enqueueFetchJob(r.Context(), app, req)
+		if err != nil {
+			log.Printf("Error queueing fetch job for %s#%s: %v", validatedGameName, validatedTagLine, err)
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeInternal, "Failed to queue fetch job"))
+			return
+		}
 
-		// Prepare pagination info
-		hasMore := len(userPerformance.Matches) == count
-		pagination := PaginationInfo{
-			Offset:  offset,
-			Limit:   count,
-			Total:   -1, // We don't know the total
-			HasMore: hasMore,
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/api/jobs/"+job.ID)
+		w.WriteHeader(http.StatusAccepted)
+		if err := json.NewEncoder(w).Encode(job); err != nil {
+			log.Printf("Error encoding fetch job response: %v", err)
 		}
+	}
+}
 
-		// Prepare response
-		var dashboardData PaginatedDashboardResponse
+func getFetchJobHandler(app *GlobalAppData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jobID := chi.URLParam(r, "jobId")
+		if err := ValidateJobID(jobID); err != nil {
+			writeValidationError(w, r, err, "Invalid input")
+			return
+		}
 
-		if offset == 0 {
-			// First page: include full summary
-			summary := calculateRecentGamesSummary(userPerformance.Matches, userPerformance.PUUID, userPerformance.Region, userPerformance.RiotID)
-			dashboardData = PaginatedDashboardResponse{
-				Summary:          summary,
-				Matches:          userPerformance.Matches,
-				Pagination:       pagination,
-				IncrementalStats: incrementalStats,
+		job, err := getFetchJob(r.Context(), app, jobID)
+		if err != nil {
+			log.Printf("Error loading fetch job %s: %v", jobID, err)
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeInternal, "Failed to load job"))
+			return
+		}
+		if job == nil {
+			writeAPIError(w, r, newAPIError(http.StatusNotFound, ErrCodeNotFound, "Job not found"))
+			return
+		}
+		// The dashboard serves unfiltered results; queue-filtered ones only live on the job
+		if job.Status == fetchJobStatusCompleted && job.QueueID != 0 {
+			job.Matches, err = getFetchJobResult(r.Context(), app, jobID)
+			if err != nil {
+				log.Printf("Error loading fetch job %s: %v", jobID, err)
+				writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeInternal, "Failed to load job"))
+				return
 			}
-		} else {
-			// Subsequent pages: no summary, just matches and incremental stats
-			dashboardData = PaginatedDashboardResponse{
-				Summary:          nil,
-				Matches:          userPerformance.Matches,
-				Pagination:       pagination,
-				IncrementalStats: incrementalStats,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(job); err != nil {
+			log.Printf("Error encoding fetch job response: %v", err)
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeInternal, "Failed to encode response"))
+		}
+	}
+}
+
+func getPlayerDashboardStreamHandler(app *GlobalAppData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		region := chi.URLParam(r, "region")
+		gameName := chi.URLParam(r, "gameName")
+		tagLine := chi.URLParam(r, "tagLine")
+
+		countStr := r.URL.Query().Get("count")
+		queueIDStr := r.URL.Query().Get("queueId")
+
+		// Input validation
+		validatedGameName, validatedTagLine, validatedRegion, err := ValidateAndSanitizeInput(gameName, tagLine, region)
+		if err != nil {
+			log.Printf("Input validation error: %v", err)
+			writeValidationError(w, r, err, "Invalid input")
+			return
+		}
+
+		// NoSQL injection prevention
+		if err := PreventNoSQLInjection(validatedGameName); err != nil {
+			log.Printf("Potential NoSQL injection attempt in gameName: %s", validatedGameName)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
+			return
+		}
+		if err := PreventNoSQLInjection(validatedTagLine); err != nil {
+			log.Printf("Potential NoSQL injection attempt in tagLine: %s", validatedTagLine)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
+			return
+		}
+
+		// Parameter validation
+		count, err := ValidateCount(countStr, defaultMatchCount, 100)
+		if err != nil {
+			log.Printf("Count validation error: %v", err)
+			writeValidationError(w, r, err, "Invalid count parameter")
+			return
+		}
+
+		queueID, err := validateQueueIDParam(app, queueIDStr)
+		if err != nil {
+			log.Printf("QueueID validation error: %v", err)
+			writeValidationError(w, r, err, "Invalid queueId parameter")
+			return
+		}
+
+		log.Printf("Handler: Received dashboard stream request for %s#%s in region %s, count: %d, queueId: %d", validatedGameName, validatedTagLine, validatedRegion, count, queueID)
+
+		if app.staticData == nil {
+			log.Println("Static data not yet loaded, attempting to load now.")
+			err := populateStaticData(app)
+			if err != nil {
+				log.Printf("Error populating static data on demand: %v", err)
+				writeAPIError(w, r, newAPIError(http.StatusServiceUnavailable, ErrCodeUpstreamUnavailable, "Error loading required game data. Please try again shortly."))
+				return
+			}
+		}
+
+		streamDashboard(w, r, app, validatedRegion, validatedGameName, validatedTagLine, count, queueID)
+	}
+}
+
+func getMatchScoreboardHandler(app *GlobalAppData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		region := chi.URLParam(r, "region")
+		matchId := chi.URLParam(r, "matchId")
+
+		// Validate and sanitize input parameters
+		validatedRegion, validatedMatchId, err := ValidateMatchInput(region, matchId)
+		if err != nil {
+			log.Printf("Input validation error: %v", err)
+			writeValidationError(w, r, err, "Invalid input")
+			return
+		}
+
+		// Additional NoSQL injection prevention
+		if err := PreventNoSQLInjection(validatedMatchId); err != nil {
+			log.Printf("Potential NoSQL injection attempt in matchId: %s", validatedMatchId)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
+			return
+		}
+
+		log.Printf("Handler: Received match scoreboard request for match %s in region %s", validatedMatchId, validatedRegion)
+
+		if app.staticData == nil {
+			log.Println("Static data not yet loaded, attempting to load now.")
+			err := populateStaticData(app)
+			if err != nil {
+				log.Printf("Error populating static data on demand: %v", err)
+				writeAPIError(w, r, newAPIError(http.StatusServiceUnavailable, ErrCodeUpstreamUnavailable, "Error loading required game data. Please try again shortly."))
+				return
 			}
 		}
 
+		// The scoreboard only changes when static data names do, i.e. with a new patch
+		if notModified(w, r, staticDataETag(app.staticData, "scoreboard", validatedRegion, validatedMatchId), cacheControlStatic) {
+			return
+		}
+
+		match, err := getMatchDetails(app, validatedRegion, validatedMatchId)
+		if err != nil {
+			log.Printf("Error fetching match details for %s: %v", validatedMatchId, err)
+			writeError(w, r, err, "Error fetching match details")
+			return
+		}
+		if match == nil {
+			writeAPIError(w, r, newAPIError(http.StatusNotFound, ErrCodeMatchNotFound, "Match not found"))
+			return
+		}
+
+		scoreboard := buildMatchScoreboard(app, match)
+
 		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(dashboardData); err != nil {
-			log.Printf("Error encoding dashboard response for %s#%s: %v", validatedGameName, validatedTagLine, err)
-			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		if err := json.NewEncoder(w).Encode(scoreboard); err != nil {
+			log.Printf("Error encoding scoreboard response for %s: %v", validatedMatchId, err)
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeInternal, "Failed to encode response"))
+		}
+	}
+}
+
+func getPlayerBuildsHandler(app *GlobalAppData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		region := chi.URLParam(r, "region")
+		gameName := chi.URLParam(r, "gameName")
+		tagLine := chi.URLParam(r, "tagLine")
+
+		countStr := r.URL.Query().Get("count")
+		queueIDStr := r.URL.Query().Get("queueId")
+
+		// Validate and sanitize input parameters
+		validatedGameName, validatedTagLine, validatedRegion, err := ValidateAndSanitizeInput(gameName, tagLine, region)
+		if err != nil {
+			log.Printf("Input validation error: %v", err)
+			writeValidationError(w, r, err, "Invalid input")
+			return
+		}
+
+		// Additional NoSQL injection prevention
+		if err := PreventNoSQLInjection(validatedGameName); err != nil {
+			log.Printf("Potential NoSQL injection attempt in gameName: %s", validatedGameName)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
+			return
+		}
+		if err := PreventNoSQLInjection(validatedTagLine); err != nil {
+			log.Printf("Potential NoSQL injection attempt in tagLine: %s", validatedTagLine)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
+			return
+		}
+
+		// Validate count parameter
+		count, err := ValidateCount(countStr, defaultMatchCount, 100)
+		if err != nil {
+			log.Printf("Count validation error: %v", err)
+			writeValidationError(w, r, err, "Invalid count parameter")
+			return
+		}
+
+		// Validate queueID parameter
+		queueID, err := validateQueueIDParam(app, queueIDStr)
+		if err != nil {
+			log.Printf("QueueID validation error: %v", err)
+			writeValidationError(w, r, err, "Invalid queueId parameter")
+			return
+		}
+
+		log.Printf("Handler: Received player builds request for %s#%s in region %s, count: %d, queueId: %d", validatedGameName, validatedTagLine, validatedRegion, count, queueID)
+
+		if app.staticData == nil {
+			log.Println("Static data not yet loaded, attempting to load now.")
+			err := populateStaticData(app)
+			if err != nil {
+				log.Printf("Error populating static data on demand: %v", err)
+				writeAPIError(w, r, newAPIError(http.StatusServiceUnavailable, ErrCodeUpstreamUnavailable, "Error loading required game data. Please try again shortly."))
+				return
+			}
+		}
+
+		// Each match can also need its timeline fetched, far beyond the server WriteTimeout
+		rc := http.NewResponseController(w)
+		if err := rc.SetWriteDeadline(time.Now().Add(defaultTimeout * time.Duration(2*count+5))); err != nil {
+			log.Printf("Builds: Could not extend write deadline: %v", err)
+		}
+
+		builds, err := fetchPlayerBuilds(app, validatedRegion, validatedGameName, validatedTagLine, count, queueID)
+		if err != nil {
+			log.Printf("Error fetching player builds for %s#%s: %v", validatedGameName, validatedTagLine, err)
+			writeError(w, r, err, "Error fetching player builds")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(builds); err != nil {
+			log.Printf("Error encoding builds response for %s#%s: %v", validatedGameName, validatedTagLine, err)
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeInternal, "Failed to encode response"))
+		}
+	}
+}
+
+func getItemStatsHandler(app *GlobalAppData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		if app.staticData == nil {
+			log.Println("Static data not yet loaded, attempting to load now.")
+			err := populateStaticData(app)
+			if err != nil {
+				log.Printf("Error populating static data on demand: %v", err)
+				writeAPIError(w, r, newAPIError(http.StatusServiceUnavailable, ErrCodeUpstreamUnavailable, "Error loading required game data. Please try again shortly."))
+				return
+			}
+		}
+
+		championID, err := resolveChampionFilter(app.staticData, query.Get("champion"))
+		if err != nil {
+			writeValidationError(w, r, err, "Invalid champion parameter")
+			return
+		}
+
+		role, err := ValidateRole(query.Get("role"))
+		if err != nil {
+			writeValidationError(w, r, err, "Invalid role parameter")
+			return
+		}
+
+		patch, err := ValidatePatch(query.Get("patch"))
+		if err != nil {
+			writeValidationError(w, r, err, "Invalid patch parameter")
+			return
+		}
+
+		queueID, err := validateQueueIDParam(app, query.Get("queueId"))
+		if err != nil {
+			writeValidationError(w, r, err, "Invalid queueId parameter")
+			return
+		}
+
+		limit, err := ValidateCount(query.Get("limit"), defaultItemStatsLimit, 200)
+		if err != nil {
+			writeValidationError(w, r, err, "Invalid limit parameter")
+			return
+		}
+
+		filters := ItemStatsFilters{ChampionID: championID, Role: role, Patch: patch, QueueID: queueID}
+		stats, err := queryItemStats(r.Context(), app, filters, limit)
+		if err != nil {
+			log.Printf("Error querying item stats: %v", err)
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeInternal, "Failed to fetch item stats."))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(stats); err != nil {
+			log.Printf("Error encoding item stats response: %v", err)
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeInternal, "Failed to encode response"))
+		}
+	}
+}
+
+func getChampionStatsHandler(app *GlobalAppData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		if app.staticData == nil {
+			log.Println("Static data not yet loaded, attempting to load now.")
+			err := populateStaticData(app)
+			if err != nil {
+				log.Printf("Error populating static data on demand: %v", err)
+				writeAPIError(w, r, newAPIError(http.StatusServiceUnavailable, ErrCodeUpstreamUnavailable, "Error loading required game data. Please try again shortly."))
+				return
+			}
+		}
+
+		championID, err := resolveChampionFilter(app.staticData, query.Get("champion"))
+		if err != nil {
+			writeValidationError(w, r, err, "Invalid champion parameter")
+			return
+		}
+
+		role, err := ValidateRole(query.Get("role"))
+		if err != nil {
+			writeValidationError(w, r, err, "Invalid role parameter")
+			return
+		}
+
+		patch, err := ValidatePatch(query.Get("patch"))
+		if err != nil {
+			writeValidationError(w, r, err, "Invalid patch parameter")
+			return
+		}
+
+		queueID, err := validateQueueIDParam(app, query.Get("queueId"))
+		if err != nil {
+			writeValidationError(w, r, err, "Invalid queueId parameter")
+			return
+		}
+
+		minGames, err := ValidateCount(query.Get("minGames"), defaultChampionStatsMinGames, 10000)
+		if err != nil {
+			writeValidationError(w, r, err, "Invalid minGames parameter")
+			return
+		}
+
+		filters := ChampionTierFilters{ChampionID: championID, Role: role, Patch: patch, QueueID: queueID, MinGames: minGames}
+		stats, err := queryChampionStats(r.Context(), app, filters)
+		if err != nil {
+			log.Printf("Error querying champion stats: %v", err)
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeInternal, "Failed to fetch champion stats."))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(stats); err != nil {
+			log.Printf("Error encoding champion stats response: %v", err)
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeInternal, "Failed to encode response"))
+		}
+	}
+}
+
+func getPlayerRunesHandler(app *GlobalAppData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		region := chi.URLParam(r, "region")
+		gameName := chi.URLParam(r, "gameName")
+		tagLine := chi.URLParam(r, "tagLine")
+
+		countStr := r.URL.Query().Get("count")
+		queueIDStr := r.URL.Query().Get("queueId")
+
+		// Validate and sanitize input parameters
+		validatedGameName, validatedTagLine, validatedRegion, err := ValidateAndSanitizeInput(gameName, tagLine, region)
+		if err != nil {
+			log.Printf("Input validation error: %v", err)
+			writeValidationError(w, r, err, "Invalid input")
+			return
+		}
+
+		// Additional NoSQL injection prevention
+		if err := PreventNoSQLInjection(validatedGameName); err != nil {
+			log.Printf("Potential NoSQL injection attempt in gameName: %s", validatedGameName)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
+			return
+		}
+		if err := PreventNoSQLInjection(validatedTagLine); err != nil {
+			log.Printf("Potential NoSQL injection attempt in tagLine: %s", validatedTagLine)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
+			return
+		}
+
+		// Validate count parameter
+		count, err := ValidateCount(countStr, defaultMatchCount, 100)
+		if err != nil {
+			log.Printf("Count validation error: %v", err)
+			writeValidationError(w, r, err, "Invalid count parameter")
+			return
+		}
+
+		// Validate queueID parameter
+		queueID, err := validateQueueIDParam(app, queueIDStr)
+		if err != nil {
+			log.Printf("QueueID validation error: %v", err)
+			writeValidationError(w, r, err, "Invalid queueId parameter")
+			return
+		}
+
+		log.Printf("Handler: Received player runes request for %s#%s in region %s, count: %d, queueId: %d", validatedGameName, validatedTagLine, validatedRegion, count, queueID)
+
+		if app.staticData == nil {
+			log.Println("Static data not yet loaded, attempting to load now.")
+			err := populateStaticData(app)
+			if err != nil {
+				log.Printf("Error populating static data on demand: %v", err)
+				writeAPIError(w, r, newAPIError(http.StatusServiceUnavailable, ErrCodeUpstreamUnavailable, "Error loading required game data. Please try again shortly."))
+				return
+			}
+		}
+
+		runes, err := fetchPlayerRunes(app, validatedRegion, validatedGameName, validatedTagLine, count, queueID)
+		if err != nil {
+			log.Printf("Error fetching player runes for %s#%s: %v", validatedGameName, validatedTagLine, err)
+			writeError(w, r, err, "Error fetching player runes")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(runes); err != nil {
+			log.Printf("Error encoding runes response for %s#%s: %v", validatedGameName, validatedTagLine, err)
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeInternal, "Failed to encode response"))
+		}
+	}
+}
+
+func getPlayerHeatmapHandler(app *GlobalAppData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		region := chi.URLParam(r, "region")
+		gameName := chi.URLParam(r, "gameName")
+		tagLine := chi.URLParam(r, "tagLine")
+
+		countStr := r.URL.Query().Get("count")
+		queueIDStr := r.URL.Query().Get("queueId")
+		timezoneStr := r.URL.Query().Get("tz")
+
+		// Validate and sanitize input parameters
+		validatedGameName, validatedTagLine, validatedRegion, err := ValidateAndSanitizeInput(gameName, tagLine, region)
+		if err != nil {
+			log.Printf("Input validation error: %v", err)
+			writeValidationError(w, r, err, "Invalid input")
+			return
+		}
+
+		// Additional NoSQL injection prevention
+		if err := PreventNoSQLInjection(validatedGameName); err != nil {
+			log.Printf("Potential NoSQL injection attempt in gameName: %s", validatedGameName)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
+			return
+		}
+		if err := PreventNoSQLInjection(validatedTagLine); err != nil {
+			log.Printf("Potential NoSQL injection attempt in tagLine: %s", validatedTagLine)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
+			return
+		}
+
+		// Validate count parameter
+		count, err := ValidateCount(countStr, defaultMatchCount, 100)
+		if err != nil {
+			log.Printf("Count validation error: %v", err)
+			writeValidationError(w, r, err, "Invalid count parameter")
+			return
+		}
+
+		// Validate queueID parameter
+		queueID, err := validateQueueIDParam(app, queueIDStr)
+		if err != nil {
+			log.Printf("QueueID validation error: %v", err)
+			writeValidationError(w, r, err, "Invalid queueId parameter")
+			return
+		}
+
+		// Validate timezone parameter
+		loc, err := ValidateTimezone(timezoneStr)
+		if err != nil {
+			log.Printf("Timezone validation error: %v", err)
+			writeValidationError(w, r, err, "Invalid tz parameter")
+			return
+		}
+
+		log.Printf("Handler: Received player heatmap request for %s#%s in region %s, count: %d, queueId: %d, tz: %s", validatedGameName, validatedTagLine, validatedRegion, count, queueID, loc)
+
+		heatmap, err := fetchPlayerHeatmap(app, validatedRegion, validatedGameName, validatedTagLine, count, queueID, loc)
+		if err != nil {
+			log.Printf("Error fetching player heatmap for %s#%s: %v", validatedGameName, validatedTagLine, err)
+			writeError(w, r, err, "Error fetching player heatmap")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(heatmap); err != nil {
+			log.Printf("Error encoding heatmap response for %s#%s: %v", validatedGameName, validatedTagLine, err)
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeInternal, "Failed to encode response"))
+		}
+	}
+}
+
+func getPlayerExportHandler(app *GlobalAppData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		region := chi.URLParam(r, "region")
+		gameName := chi.URLParam(r, "gameName")
+		tagLine := chi.URLParam(r, "tagLine")
+
+		query := r.URL.Query()
+
+		// Validate and sanitize input parameters
+		validatedGameName, validatedTagLine, validatedRegion, err := ValidateAndSanitizeInput(gameName, tagLine, region)
+		if err != nil {
+			log.Printf("Input validation error: %v", err)
+			writeValidationError(w, r, err, "Invalid input")
+			return
+		}
+
+		// Additional NoSQL injection prevention
+		if err := PreventNoSQLInjection(validatedGameName); err != nil {
+			log.Printf("Potential NoSQL injection attempt in gameName: %s", validatedGameName)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
+			return
+		}
+		if err := PreventNoSQLInjection(validatedTagLine); err != nil {
+			log.Printf("Potential NoSQL injection attempt in tagLine: %s", validatedTagLine)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
+			return
+		}
+
+		format := strings.ToLower(SanitizeString(query.Get("format")))
+		if format == "" {
+			format = exportFormatCSV
+		}
+		if _, ok := exportContentTypes[format]; !ok {
+			writeValidationError(w, r, ValidationError{Field: "format", Message: "format must be csv, ndjson or parquet"}, "Invalid format parameter")
+			return
+		}
+
+		queueID, err := validateQueueIDParam(app, query.Get("queueId"))
+		if err != nil {
+			writeValidationError(w, r, err, "Invalid queueId parameter")
+			return
+		}
+
+		startTime, endTime, err := ValidateTimeRange(query.Get("startTime"), query.Get("endTime"))
+		if err != nil {
+			writeValidationError(w, r, err, "Invalid time range")
+			return
+		}
+
+		if app.staticData == nil {
+			log.Println("Static data not yet loaded, attempting to load now.")
+			err := populateStaticData(app)
+			if err != nil {
+				log.Printf("Error populating static data on demand: %v", err)
+				writeAPIError(w, r, newAPIError(http.StatusServiceUnavailable, ErrCodeUpstreamUnavailable, "Error loading required game data. Please try again shortly."))
+				return
+			}
+		}
+
+		puuid, err := getPUUID(app, validatedRegion, validatedGameName, validatedTagLine)
+		if err != nil {
+			log.Printf("Error getting PUUID for export of %s#%s: %v", validatedGameName, validatedTagLine, err)
+			writeError(w, r, err, "Could not find player")
+			return
+		}
+		if err := PreventNoSQLInjection(puuid); err != nil {
+			log.Printf("Potential injection attempt in PUUID: %s", puuid)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid player data"))
+			return
+		}
+
+		collection := app.mongoClient.Database(app.mongoDatabase).Collection("userperformances")
+		stored, err := collection.CountDocuments(r.Context(), bson.M{"_id": puuid, "region": validatedRegion})
+		if err != nil {
+			log.Printf("Error checking stored matches for %s: %v", puuid, err)
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeInternal, "Failed to export matches"))
+			return
+		}
+		if stored == 0 {
+			writeAPIError(w, r, newAPIError(http.StatusNotFound, ErrCodeNotFound, "No stored matches for this player yet. Load their dashboard first."))
+			return
+		}
+
+		filters := ExportFilters{QueueID: queueID, StartTime: startTime, EndTime: endTime}
+		cursor, err := openExportCursor(r.Context(), app, validatedRegion, puuid, filters)
+		if err != nil {
+			log.Printf("Error opening export for %s: %v", puuid, err)
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeInternal, "Failed to export matches"))
+			return
+		}
+
+		log.Printf("Handler: Exporting matches for %s#%s in region %s as %s", validatedGameName, validatedTagLine, validatedRegion, format)
+		filename := fmt.Sprintf("%s-%s-matches.%s", validatedGameName, validatedTagLine, format)
+		if err := streamMatchExport(r.Context(), w, app, cursor, format, filename); err != nil {
+			// Headers are already sent, so the client just sees a truncated file
+			log.Printf("Error streaming export for %s#%s: %v", validatedGameName, validatedTagLine, err)
 		}
 	}
 }
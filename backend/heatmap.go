@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"time"
+	_ "time/tzdata" // The runtime image has no zoneinfo, so embed it for user-supplied timezones
+)
+
+// calculateHeatmap buckets matches by the weekday and hour they started in loc
+func calculateHeatmap(matches []PlayerMatchStats, loc *time.Location) [7][24]HeatmapCell {
+	type cellAgg struct {
+		games, wins            int
+		kills, deaths, assists int
+	}
+	var aggs [7][24]cellAgg
+
+	for _, match := range matches {
+		started := time.UnixMilli(match.GameCreation).In(loc)
+		agg := &aggs[started.Weekday()][started.Hour()]
+		agg.games++
+		if match.Win {
+			agg.wins++
+		}
+		agg.kills += match.Kills
+		agg.deaths += match.Deaths
+		agg.assists += match.Assists
+	}
+
+	var cells [7][24]HeatmapCell
+	for day := range aggs {
+		for hour, agg := range aggs[day] {
+			if agg.games == 0 {
+				continue
+			}
+			cell := HeatmapCell{
+				Games:   agg.games,
+				Wins:    agg.wins,
+				WinRate: float64(agg.wins) / float64(agg.games) * 100,
+			}
+			if agg.deaths > 0 {
+				cell.AvgKDA = float64(agg.kills+agg.assists) / float64(agg.deaths)
+			} else {
+				cell.AvgKDA = float64(agg.kills + agg.assists)
+			}
+			cells[day][hour] = cell
+		}
+	}
+	return cells
+}
+
+// fetchPlayerHeatmap builds a weekday/hour heatmap over a player's recent matches
+func fetchPlayerHeatmap(app *GlobalAppData, region, gameName, tagLine string, count, queueID int, loc *time.Location) (*PlayerHeatmapResponse, error) {
+	performance, err := fetchAndStoreUserPerformance(app, region, gameName, tagLine, count, queueID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user performance: %w", err)
+	}
+
+	return &PlayerHeatmapResponse{
+		PUUID:        performance.PUUID,
+		Region:       performance.Region,
+		RiotID:       performance.RiotID,
+		Timezone:     loc.String(),
+		TotalMatches: len(performance.Matches),
+		Cells:        calculateHeatmap(performance.Matches, loc),
+	}, nil
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	itemStatsCollection             = "itemstats"
+	defaultItemStatsRefreshInterval = 6 * time.Hour
+	itemStatsStartupDelay           = 1 * time.Minute
+	itemStatsQueryCacheTTL          = 1 * time.Hour
+	itemStatsComputeTimeout         = 10 * time.Minute
+	defaultItemStatsLimit           = 50
+	unknownPatch                    = "unknown"
+)
+
+// getItemStatsRefreshInterval returns how often item stats are recomputed
+func getItemStatsRefreshInterval() time.Duration {
+	if intervalStr := os.Getenv("ITEM_STATS_REFRESH_INTERVAL"); intervalStr != "" {
+		if interval, err := time.ParseDuration(intervalStr); err == nil && interval >= 5*time.Minute {
+			return interval
+		}
+	}
+	return defaultItemStatsRefreshInterval
+}
+
+func itemStatsBucketID(championID int, role, patch string, queueID int) string {
+	return fmt.Sprintf("%d|%s|%s|%d", championID, role, patch, queueID)
+}
+
+// startItemStatsScheduler recomputes item stats shortly after startup and then on an interval
+func startItemStatsScheduler(ctx context.Context, app *GlobalAppData) {
+	interval := getItemStatsRefreshInterval()
+	log.Printf("Item stats: Scheduler started (interval: %v)", interval)
+
+	go func() {
+		timer := time.NewTimer(itemStatsStartupDelay)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+
+			if err := computeItemStats(ctx, app); err != nil {
+				log.Printf("Item stats: Error computing item stats: %v", err)
+			}
+			timer.Reset(jitterDuration(interval, trackerRefreshJitter))
+		}
+	}()
+}
+
+// computeItemStats scans every stored match once and rewrites the item stats buckets
+func computeItemStats(ctx context.Context, app *GlobalAppData) error {
+	if app.staticData == nil {
+		return fmt.Errorf("static data not loaded")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, itemStatsComputeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	source := app.mongoClient.Database(app.mongoDatabase).Collection("userperformances")
+
+	projection := bson.M{
+		"matches.championId":   1,
+		"matches.championName": 1,
+		"matches.teamPosition": 1,
+		"matches.gameMode":     1,
+		"matches.patch":        1,
+		"matches.queueId":      1,
+		"matches.win":          1,
+		"matches.items":        1,
+	}
+	cursor, err := source.Find(ctx, bson.M{}, options.Find().SetProjection(projection))
+	if err != nil {
+		return fmt.Errorf("failed to scan user performances: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	type bucketAgg struct {
+		bucket ItemStatsBucket
+		items  map[int]*ItemStatsCounts
+	}
+	buckets := map[string]*bucketAgg{}
+	matchCount := 0
+
+	for cursor.Next(ctx) {
+		var doc UserPerformance
+		if err := cursor.Decode(&doc); err != nil {
+			log.Printf("Item stats: Skipping undecodable document: %v", err)
+			continue
+		}
+
+		for _, match := range doc.Matches {
+			role := normalizeRole(match.TeamPosition, match.GameMode)
+			patch := match.Patch
+			if patch == "" {
+				patch = unknownPatch
+			}
+
+			id := itemStatsBucketID(match.ChampionID, role, patch, match.QueueID)
+			agg, ok := buckets[id]
+			if !ok {
+				agg = &bucketAgg{
+					bucket: ItemStatsBucket{
+						ID:           id,
+						ChampionID:   match.ChampionID,
+						ChampionName: match.ChampionName,
+						Role:         role,
+						Patch:        patch,
+						QueueID:      match.QueueID,
+					},
+					items: map[int]*ItemStatsCounts{},
+				}
+				buckets[id] = agg
+			}
+
+			agg.bucket.Games++
+			if match.Win {
+				agg.bucket.Wins++
+			}
+			matchCount++
+
+			// Count each finished item once per game
+			seen := map[int]bool{}
+			for _, itemID := range match.Items {
+				if seen[itemID] || !isCompletedItem(app.staticData, itemID) {
+					continue
+				}
+				seen[itemID] = true
+
+				counts, ok := agg.items[itemID]
+				if !ok {
+					counts = &ItemStatsCounts{ItemID: itemID}
+					agg.items[itemID] = counts
+				}
+				counts.Picks++
+				if match.Win {
+					counts.Wins++
+				}
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed while scanning user performances: %w", err)
+	}
+
+	target := app.mongoClient.Database(app.mongoDatabase).Collection(itemStatsCollection)
+	runStamp := time.Now().Unix()
+
+	writes := make([]mongo.WriteModel, 0, len(buckets))
+	for _, agg := range buckets {
+		agg.bucket.UpdatedAt = runStamp
+		agg.bucket.Items = make([]ItemStatsCounts, 0, len(agg.items))
+		for _, counts := range agg.items {
+			agg.bucket.Items = append(agg.bucket.Items, *counts)
+		}
+		writes = append(writes, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": agg.bucket.ID}).
+			SetReplacement(agg.bucket).
+			SetUpsert(true))
+	}
+
+	if len(writes) > 0 {
+		if _, err := target.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+			return fmt.Errorf("failed to write item stats: %w", err)
+		}
+	}
+
+	// Drop buckets that no longer have any games
+	if _, err := target.DeleteMany(ctx, bson.M{"updatedAt": bson.M{"$lt": runStamp}}); err != nil {
+		log.Printf("Item stats: Error removing stale buckets: %v", err)
+	}
+
+	log.Printf("Item stats: Computed %d buckets from %d matches in %v", len(buckets), matchCount, time.Since(start))
+	return nil
+}
+
+// queryItemStats merges the precomputed buckets matching the filters into one ranked item list
+func queryItemStats(ctx context.Context, app *GlobalAppData, filters ItemStatsFilters, limit int) (*ItemStatsResponse, error) {
+	cacheKey := fmt.Sprintf("itemstats:c%d:r%s:p%s:q%d:l%d", filters.ChampionID, filters.Role, filters.Patch, filters.QueueID, limit)
+
+	val, err := app.redisClient.Get(ctx, cacheKey).Result()
+	if err == nil {
+		var cached ItemStatsResponse
+		if err := json.Unmarshal([]byte(val), &cached); err == nil {
+			return &cached, nil
+		}
+	} else if err != redis.Nil {
+		log.Printf("Error fetching item stats from Redis: %v. Proceeding to query MongoDB.", err)
+	}
+
+	query := bson.M{}
+	if filters.ChampionID != 0 {
+		query["championId"] = filters.ChampionID
+	}
+	if filters.Role != "" {
+		query["role"] = filters.Role
+	}
+	if filters.Patch != "" {
+		query["patch"] = filters.Patch
+	}
+	if filters.QueueID != 0 {
+		query["queueId"] = filters.QueueID
+	}
+
+	collection := app.mongoClient.Database(app.mongoDatabase).Collection(itemStatsCollection)
+	cursor, err := collection.Find(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query item stats: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var bucketsFound []ItemStatsBucket
+	if err := cursor.All(ctx, &bucketsFound); err != nil {
+		return nil, fmt.Errorf("failed to decode item stats: %w", err)
+	}
+
+	response := &ItemStatsResponse{Filters: filters, Items: []ItemStatsEntry{}}
+	merged := map[int]*ItemStatsCounts{}
+	for _, bucket := range bucketsFound {
+		response.Games += bucket.Games
+		if bucket.UpdatedAt > response.GeneratedAt {
+			response.GeneratedAt = bucket.UpdatedAt
+		}
+		for _, counts := range bucket.Items {
+			m, ok := merged[counts.ItemID]
+			if !ok {
+				m = &ItemStatsCounts{ItemID: counts.ItemID}
+				merged[counts.ItemID] = m
+			}
+			m.Picks += counts.Picks
+			m.Wins += counts.Wins
+		}
+	}
+
+	for itemID, counts := range merged {
+		entry := ItemStatsEntry{
+			Item:  resolveItemRef(app.staticData, itemID),
+			Tags:  []string{},
+			Picks: counts.Picks,
+			Wins:  counts.Wins,
+		}
+		if app.staticData != nil {
+			if item, ok := app.staticData.Items[strconv.Itoa(itemID)]; ok {
+				entry.Tags = item.Tags
+				entry.Depth = item.Depth
+				entry.Gold = item.Gold.Total
+			}
+		}
+		if response.Games > 0 {
+			entry.PickRate = float64(counts.Picks) / float64(response.Games) * 100
+		}
+		if counts.Picks > 0 {
+			entry.WinRate = float64(counts.Wins) / float64(counts.Picks) * 100
+		}
+		response.Items = append(response.Items, entry)
+	}
+
+	sort.Slice(response.Items, func(i, j int) bool {
+		if response.Items[i].Picks != response.Items[j].Picks {
+			return response.Items[i].Picks > response.Items[j].Picks
+		}
+		return response.Items[i].Item.ID < response.Items[j].Item.ID
+	})
+	if len(response.Items) > limit {
+		response.Items = response.Items[:limit]
+	}
+
+	// Move Redis caching off the critical path - run asynchronously
+	go func(key string, data ItemStatsResponse) {
+		cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if dataJSON, err := json.Marshal(data); err == nil {
+			_ = app.redisClient.Set(cacheCtx, key, dataJSON, itemStatsQueryCacheTTL).Err()
+		}
+	}(cacheKey, *response)
+
+	return response, nil
+}
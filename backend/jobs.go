@@ -0,0 +1,440 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	fetchJobQueueKey       = "jobs:fetch:queue"
+	fetchJobInstancesKey   = "jobs:fetch:instances" // Server instances running workers, each with a processing list
+	fetchJobTTL            = 24 * time.Hour
+	fetchJobActiveTTL      = 2 * time.Minute // Lets the active marker of a crashed worker's job lapse
+	fetchJobLeaseTTL       = 2 * time.Minute // Hands a dead instance's jobs back to the queue
+	fetchJobHeartbeat      = 30 * time.Second
+	fetchJobPollTimeout    = 5 * time.Second
+	defaultFetchJobWorkers = 2
+
+	fetchJobStatusQueued    = "queued"
+	fetchJobStatusRunning   = "running"
+	fetchJobStatusCompleted = "completed"
+	fetchJobStatusFailed    = "failed"
+)
+
+func fetchJobKey(jobID string) string {
+	return fmt.Sprintf("jobs:fetch:%s", jobID)
+}
+
+// fetchJobResultKey holds the matches of a queue-filtered job, which the dashboard cache can't
+func fetchJobResultKey(jobID string) string {
+	return fmt.Sprintf("jobs:fetch:result:%s", jobID)
+}
+
+// fetchJobProcessingKey lists the jobs an instance's workers have taken but not finished
+func fetchJobProcessingKey(instanceID string) string {
+	return fmt.Sprintf("jobs:fetch:processing:%s", instanceID)
+}
+
+// fetchJobLeaseKey exists while an instance is alive; its heartbeat keeps it from expiring
+func fetchJobLeaseKey(instanceID string) string {
+	return fmt.Sprintf("jobs:fetch:lease:%s", instanceID)
+}
+
+// fetchJobActiveKey dedupes identical jobs so repeated clicks share one job. A queued job holds
+// it as long as the job itself; once running it expires unless the worker keeps refreshing it,
+// so a job lost with its worker stops being reused.
+func fetchJobActiveKey(region, gameName, tagLine string, count, queueID int) string {
+	return fmt.Sprintf("jobs:fetch:active:%s:%s:%s:%d:q%d", region, strings.ToLower(gameName), strings.ToLower(tagLine), count, queueID)
+}
+
+func newFetchJobID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate job ID: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// getFetchJobWorkerCount returns how many fetch job workers to run
+func getFetchJobWorkerCount() int {
+	if workersStr := os.Getenv("FETCH_JOB_WORKERS"); workersStr != "" {
+		if workers, err := strconv.Atoi(workersStr); err == nil && workers > 0 && workers <= 16 {
+			return workers
+		}
+	}
+	return defaultFetchJobWorkers
+}
+
+// enqueueFetchJob stores a new job and pushes it onto the queue.
+// If an identical job is still queued or running, that job is returned instead.
+func enqueueFetchJob(ctx context.Context, app *GlobalAppData, req FetchJobRequest) (*FetchJob, error) {
+	jobID, err := newFetchJobID()
+	if err != nil {
+		return nil, err
+	}
+
+	activeKey := fetchJobActiveKey(req.Region, req.GameName, req.TagLine, req.Count, req.QueueID)
+	claimed, err := app.redisClient.SetNX(ctx, activeKey, jobID, fetchJobTTL).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim job slot: %w", err)
+	}
+	if !claimed {
+		existingID, err := app.redisClient.Get(ctx, activeKey).Result()
+		if err == nil {
+			if existing, err := getFetchJob(ctx, app, existingID); err == nil && existing != nil {
+				log.Printf("Jobs: Reusing active job %s for %s#%s", existing.ID, req.GameName, req.TagLine)
+				return existing, nil
+			}
+		}
+		// The active marker points at an expired job, take it over
+		if err := app.redisClient.Set(ctx, activeKey, jobID, fetchJobTTL).Err(); err != nil {
+			return nil, fmt.Errorf("failed to claim job slot: %w", err)
+		}
+	}
+
+	now := time.Now().Unix()
+	job := FetchJob{
+		ID:        jobID,
+		Status:    fetchJobStatusQueued,
+		Region:    req.Region,
+		GameName:  req.GameName,
+		TagLine:   req.TagLine,
+		Count:     req.Count,
+		QueueID:   req.QueueID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	key := fetchJobKey(jobID)
+	pipe := app.redisClient.TxPipeline()
+	pipe.HSet(ctx, key, map[string]interface{}{
+		"id":        job.ID,
+		"status":    job.Status,
+		"region":    job.Region,
+		"gameName":  job.GameName,
+		"tagLine":   job.TagLine,
+		"count":     job.Count,
+		"queueId":   job.QueueID,
+		"total":     0,
+		"fetched":   0,
+		"failed":    0,
+		"createdAt": job.CreatedAt,
+		"updatedAt": job.UpdatedAt,
+	})
+	pipe.Expire(ctx, key, fetchJobTTL)
+	pipe.LPush(ctx, fetchJobQueueKey, jobID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		app.redisClient.Del(ctx, activeKey)
+		return nil, fmt.Errorf("failed to enqueue job: %w", err)
+	}
+
+	log.Printf("Jobs: Queued job %s for %s#%s (count: %d, queueId: %d)", jobID, req.GameName, req.TagLine, req.Count, req.QueueID)
+	return &job, nil
+}
+
+// getFetchJob loads a job by ID, returning nil if it does not exist or has expired
+func getFetchJob(ctx context.Context, app *GlobalAppData, jobID string) (*FetchJob, error) {
+	result := app.redisClient.HGetAll(ctx, fetchJobKey(jobID))
+	fields, err := result.Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load job %s: %w", jobID, err)
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	var job FetchJob
+	if err := result.Scan(&job); err != nil {
+		return nil, fmt.Errorf("failed to decode job %s: %w", jobID, err)
+	}
+	return &job, nil
+}
+
+// getFetchJobResult loads the matches a queue-filtered job stored, or nil when there are none
+func getFetchJobResult(ctx context.Context, app *GlobalAppData, jobID string) ([]PlayerMatchStats, error) {
+	val, err := app.redisClient.Get(ctx, fetchJobResultKey(jobID)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load result of job %s: %w", jobID, err)
+	}
+	var matches []PlayerMatchStats
+	if err := json.Unmarshal([]byte(val), &matches); err != nil {
+		return nil, fmt.Errorf("failed to decode result of job %s: %w", jobID, err)
+	}
+	return matches, nil
+}
+
+// requeueStaleFetchJobs puts the jobs of instances whose lease has lapsed back on the queue.
+// A job whose active marker now belongs to a newer identical job is dropped instead.
+func requeueStaleFetchJobs(ctx context.Context, app *GlobalAppData) {
+	instanceIDs, err := app.redisClient.SMembers(ctx, fetchJobInstancesKey).Result()
+	if err != nil {
+		log.Printf("Jobs: Failed to read worker instances: %v", err)
+		return
+	}
+
+	for _, instanceID := range instanceIDs {
+		alive, err := app.redisClient.Exists(ctx, fetchJobLeaseKey(instanceID)).Result()
+		if err != nil {
+			log.Printf("Jobs: Could not check lease of instance %s: %v", instanceID, err)
+			continue
+		}
+		if alive > 0 {
+			continue
+		}
+
+		processingKey := fetchJobProcessingKey(instanceID)
+		// Watch the list so only one instance hands the jobs back
+		err = app.redisClient.Watch(ctx, func(tx *redis.Tx) error {
+			jobIDs, err := tx.LRange(ctx, processingKey, 0, -1).Result()
+			if err != nil {
+				return err
+			}
+			// Job ID to active marker for each job to put back
+			requeued := map[string]string{}
+			dropped := []string{}
+			for _, jobID := range jobIDs {
+				job, err := getFetchJob(ctx, app, jobID)
+				if err != nil {
+					return err
+				}
+				if job == nil {
+					continue
+				}
+				activeKey := fetchJobActiveKey(job.Region, job.GameName, job.TagLine, job.Count, job.QueueID)
+				owner, err := app.redisClient.Get(ctx, activeKey).Result()
+				if err != nil && err != redis.Nil {
+					return err
+				}
+				if err == nil && owner != jobID {
+					dropped = append(dropped, jobID)
+				} else {
+					requeued[jobID] = activeKey
+				}
+			}
+
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				now := time.Now().Unix()
+				for _, jobID := range dropped {
+					pipe.HSet(ctx, fetchJobKey(jobID), "status", fetchJobStatusFailed, "error", "job was interrupted", "updatedAt", now)
+				}
+				// The right end is popped next, so interrupted jobs go first
+				for _, jobID := range jobIDs {
+					activeKey, ok := requeued[jobID]
+					if !ok {
+						continue
+					}
+					pipe.Set(ctx, activeKey, jobID, fetchJobTTL)
+					pipe.HSet(ctx, fetchJobKey(jobID), "status", fetchJobStatusQueued, "updatedAt", now)
+					pipe.RPush(ctx, fetchJobQueueKey, jobID)
+				}
+				pipe.Del(ctx, processingKey)
+				pipe.SRem(ctx, fetchJobInstancesKey, instanceID)
+				return nil
+			})
+			if err == nil && len(requeued)+len(dropped) > 0 {
+				log.Printf("Jobs: Requeued %d and dropped %d interrupted jobs of instance %s", len(requeued), len(dropped), instanceID)
+			}
+			return err
+		}, processingKey)
+		if err != nil && err != redis.TxFailedErr {
+			log.Printf("Jobs: Failed to requeue jobs of instance %s: %v", instanceID, err)
+		}
+	}
+}
+
+// startFetchJobWorkers runs background workers that drain the fetch job queue until ctx is cancelled
+func startFetchJobWorkers(ctx context.Context, app *GlobalAppData) {
+	instanceID, err := newFetchJobID()
+	if err != nil {
+		log.Printf("Jobs: Not starting fetch job workers: %v", err)
+		return
+	}
+	leaseKey := fetchJobLeaseKey(instanceID)
+	processingKey := fetchJobProcessingKey(instanceID)
+	if err := app.redisClient.Set(ctx, leaseKey, time.Now().Unix(), fetchJobLeaseTTL).Err(); err != nil {
+		log.Printf("Jobs: Failed to take worker lease: %v", err)
+	}
+	if err := app.redisClient.SAdd(ctx, fetchJobInstancesKey, instanceID).Err(); err != nil {
+		log.Printf("Jobs: Failed to register worker instance: %v", err)
+	}
+
+	// Keep this instance's lease alive and pick up the jobs of instances that stopped
+	requeueStaleFetchJobs(ctx, app)
+	go func() {
+		ticker := time.NewTicker(fetchJobHeartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				// Registering again covers an instance that was taken for dead while Redis was unreachable
+				pipe := app.redisClient.TxPipeline()
+				pipe.Set(ctx, leaseKey, time.Now().Unix(), fetchJobLeaseTTL)
+				pipe.SAdd(ctx, fetchJobInstancesKey, instanceID)
+				if _, err := pipe.Exec(ctx); err != nil {
+					log.Printf("Jobs: Failed to renew worker lease: %v", err)
+				}
+				requeueStaleFetchJobs(ctx, app)
+			}
+		}
+	}()
+
+	workers := getFetchJobWorkerCount()
+	log.Printf("Jobs: Starting %d fetch job workers", workers)
+
+	for i := 0; i < workers; i++ {
+		go func(workerID int) {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+				}
+
+				// Move rather than pop so a job survives its worker dying mid-run
+				jobID, err := app.redisClient.BLMove(ctx, fetchJobQueueKey, processingKey, "RIGHT", "LEFT", fetchJobPollTimeout).Result()
+				if err == redis.Nil {
+					continue
+				}
+				if err != nil {
+					if ctx.Err() != nil {
+						return
+					}
+					log.Printf("Jobs: Worker %d failed to poll queue: %v", workerID, err)
+					time.Sleep(fetchJobPollTimeout)
+					continue
+				}
+
+				runFetchJob(app, jobID)
+				if err := app.redisClient.LRem(context.Background(), processingKey, 1, jobID).Err(); err != nil {
+					log.Printf("Jobs: Failed to remove job %s from the processing list: %v", jobID, err)
+				}
+			}
+		}(i)
+	}
+}
+
+// runFetchJob executes a queued job, recording progress on the job hash as matches come in
+func runFetchJob(app *GlobalAppData, jobID string) {
+	ctx := context.Background()
+	key := fetchJobKey(jobID)
+
+	job, err := getFetchJob(ctx, app, jobID)
+	if err != nil || job == nil {
+		log.Printf("Jobs: Skipping job %s, could not load it: %v", jobID, err)
+		return
+	}
+	activeKey := fetchJobActiveKey(job.Region, job.GameName, job.TagLine, job.Count, job.QueueID)
+	defer app.redisClient.Del(ctx, activeKey)
+
+	// Keep the active marker alive while running; if this process dies it lapses on its own
+	app.redisClient.Set(ctx, activeKey, jobID, fetchJobActiveTTL)
+	heartbeatCtx, stopHeartbeat := context.WithCancel(ctx)
+	defer stopHeartbeat()
+	go func() {
+		ticker := time.NewTicker(fetchJobHeartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-heartbeatCtx.Done():
+				return
+			case <-ticker.C:
+				if err := app.redisClient.Expire(heartbeatCtx, activeKey, fetchJobActiveTTL).Err(); err != nil {
+					log.Printf("Jobs: Failed to refresh active marker of job %s: %v", jobID, err)
+				}
+			}
+		}
+	}()
+
+	setStatus := func(fields map[string]interface{}) {
+		fields["updatedAt"] = time.Now().Unix()
+		if err := app.redisClient.HSet(ctx, key, fields).Err(); err != nil {
+			log.Printf("Jobs: Failed to update job %s: %v", jobID, err)
+		}
+	}
+	fail := func(err error) {
+		log.Printf("Jobs: Job %s failed: %v", jobID, err)
+		setStatus(map[string]interface{}{"status": fetchJobStatusFailed, "error": err.Error()})
+	}
+
+	setStatus(map[string]interface{}{"status": fetchJobStatusRunning})
+	start := time.Now()
+
+	if app.staticData == nil {
+		if err := populateStaticData(app); err != nil {
+			fail(fmt.Errorf("static data unavailable: %w", err))
+			return
+		}
+	}
+
+	puuid, err := getPUUID(app, job.Region, job.GameName, job.TagLine)
+	if err != nil {
+		fail(fmt.Errorf("error getting PUUID: %w", err))
+		return
+	}
+	if err := PreventNoSQLInjection(puuid); err != nil {
+		fail(fmt.Errorf("potential injection attempt in PUUID: %w", err))
+		return
+	}
+
+	matchIDs, err := getMatchIDs(app, job.Region, puuid, job.Count, job.QueueID, 0, 0)
+	if err != nil {
+		fail(fmt.Errorf("error getting match IDs: %w", err))
+		return
+	}
+	setStatus(map[string]interface{}{"total": len(matchIDs)})
+
+	matches := fetchMatchesConcurrentlyWithProgress(app, job.Region, matchIDs, puuid, func(stats *PlayerMatchStats) {
+		field := "fetched"
+		if stats == nil {
+			field = "failed"
+		}
+		if err := app.redisClient.HIncrBy(ctx, key, field, 1).Err(); err != nil {
+			log.Printf("Jobs: Failed to record progress for job %s: %v", jobID, err)
+		}
+	})
+
+	performance := UserPerformance{
+		PUUID:     puuid,
+		Region:    job.Region,
+		RiotID:    job.GameName + "#" + job.TagLine,
+		Matches:   matches,
+		UpdatedAt: time.Now().Unix(),
+	}
+	if performance.Matches == nil {
+		performance.Matches = []PlayerMatchStats{}
+	}
+
+	// Store exactly like a synchronous first-page fetch so the dashboard picks it up. A
+	// queue-filtered fetch would pass for the unfiltered first page, so it is kept on the job.
+	if job.QueueID == 0 {
+		performance.RankTier = rankTierWithBudget(ctx, app, nil, job.Region, puuid)
+		persistUserPerformance(app, performance, userPerformanceRedisKey(job.Region, puuid, 0))
+	} else {
+		resultJSON, err := json.Marshal(performance.Matches)
+		if err == nil {
+			err = app.redisClient.Set(ctx, fetchJobResultKey(jobID), resultJSON, fetchJobTTL).Err()
+		}
+		if err != nil {
+			fail(fmt.Errorf("failed to store result: %w", err))
+			return
+		}
+	}
+
+	setStatus(map[string]interface{}{"status": fetchJobStatusCompleted})
+	log.Printf("Jobs: Job %s completed with %d/%d matches in %v", jobID, len(matches), len(matchIDs), time.Since(start))
+}
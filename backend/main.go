@@ -4,10 +4,10 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/subtle"
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
-	"encoding/json"
 	"encoding/pem"
 	"fmt"
 	"log"
@@ -23,7 +23,6 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-redis/redis/v8"
-	"github.com/gorilla/handlers"
 	"github.com/joho/godotenv"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -66,7 +65,8 @@ func corsMiddleware(next http.Handler) http.Handler {
 		if allowedOrigins[origin] {
 			w.Header().Set("Access-Control-Allow-Origin", origin)
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID, If-None-Match")
+			w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, Retry-After, ETag, Deprecation, Sunset, Link")
 			w.Header().Set("Access-Control-Allow-Credentials", "true")
 		}
 
@@ -80,6 +80,25 @@ func corsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// adminTokenMiddleware lets through only requests bearing ADMIN_TOKEN, e.g. "Authorization: Bearer <token>".
+// Without ADMIN_TOKEN set the routes it guards are disabled.
+func adminTokenMiddleware(next http.Handler) http.Handler {
+	adminToken := os.Getenv("ADMIN_TOKEN")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if adminToken == "" {
+			writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeServerMisconfigured, "Server configuration error: ADMIN_TOKEN not set."))
+			return
+		}
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+			writeAPIError(w, r, newAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "A valid admin token is required"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // loggingMiddleware logs all incoming requests for debugging
 func loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -107,6 +126,18 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Flush lets streaming handlers push data through the wrapper
+func (rw *responseWriter) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // generateSelfSignedCert generates a self-signed certificate for development
 func generateSelfSignedCert(certFile, keyFile string) error {
 	// Generate a new private key
@@ -340,6 +371,83 @@ func createIndexes(client *mongo.Client, database string) error {
 	}
 
 	log.Println("Successfully created MongoDB indexes for userperformances collection")
+
+	trackedCollection := client.Database(database).Collection(trackedPlayersCollection)
+	_, err = trackedCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "nextRefreshAt", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create %s indexes: %v", trackedPlayersCollection, err)
+	}
+
+	log.Printf("Successfully created MongoDB indexes for %s collection", trackedPlayersCollection)
+
+	itemStatsIndexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "championId", Value: 1}, {Key: "role", Value: 1}}},
+		{Keys: bson.D{{Key: "patch", Value: 1}, {Key: "queueId", Value: 1}}},
+		{Keys: bson.D{{Key: "updatedAt", Value: 1}}},
+	}
+	_, err = client.Database(database).Collection(itemStatsCollection).Indexes().CreateMany(context.Background(), itemStatsIndexes)
+	if err != nil {
+		return fmt.Errorf("failed to create %s indexes: %v", itemStatsCollection, err)
+	}
+
+	log.Printf("Successfully created MongoDB indexes for %s collection", itemStatsCollection)
+
+	ingestedIndexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "patch", Value: 1}, {Key: "queueId", Value: 1}}},
+		{Keys: bson.D{{Key: "participants.puuid", Value: 1}}},
+	}
+	_, err = client.Database(database).Collection(ingestedMatchesCollection).Indexes().CreateMany(context.Background(), ingestedIndexes)
+	if err != nil {
+		return fmt.Errorf("failed to create %s indexes: %v", ingestedMatchesCollection, err)
+	}
+
+	log.Printf("Successfully created MongoDB indexes for %s collection", ingestedMatchesCollection)
+
+	championStatsIndexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "kind", Value: 1}, {Key: "patch", Value: 1}, {Key: "queueId", Value: 1}}},
+		{Keys: bson.D{{Key: "championId", Value: 1}, {Key: "role", Value: 1}}},
+		{Keys: bson.D{{Key: "updatedAt", Value: 1}}},
+	}
+	_, err = client.Database(database).Collection(championStatsCollection).Indexes().CreateMany(context.Background(), championStatsIndexes)
+	if err != nil {
+		return fmt.Errorf("failed to create %s indexes: %v", championStatsCollection, err)
+	}
+
+	log.Printf("Successfully created MongoDB indexes for %s collection", championStatsCollection)
+
+	benchmarkIndexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tier", Value: 1}}},
+		{Keys: bson.D{{Key: "updatedAt", Value: 1}}},
+	}
+	_, err = client.Database(database).Collection(benchmarksCollection).Indexes().CreateMany(context.Background(), benchmarkIndexes)
+	if err != nil {
+		return fmt.Errorf("failed to create %s indexes: %v", benchmarksCollection, err)
+	}
+
+	log.Printf("Successfully created MongoDB indexes for %s collection", benchmarksCollection)
+
+	_, err = client.Database(database).Collection(nameHistoryCollection).Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "names.key", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create %s indexes: %v", nameHistoryCollection, err)
+	}
+
+	log.Printf("Successfully created MongoDB indexes for %s collection", nameHistoryCollection)
+
+	searchIndexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "searchKey", Value: 1}}},
+		{Keys: bson.D{{Key: "words", Value: 1}}},
+		{Keys: bson.D{{Key: "lastSeen", Value: -1}}},
+	}
+	_, err = client.Database(database).Collection(searchIndexCollection).Indexes().CreateMany(context.Background(), searchIndexes)
+	if err != nil {
+		return fmt.Errorf("failed to create %s indexes: %v", searchIndexCollection, err)
+	}
+
+	log.Printf("Successfully created MongoDB indexes for %s collection", searchIndexCollection)
 	return nil
 }
 
@@ -422,9 +530,27 @@ func main() {
 		log.Println("Static data population complete. All static data is preloaded and cached in memory.")
 	}
 
+	// Keep tracked players warm in the background
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	startTrackedPlayerScheduler(backgroundCtx, &app)
+
+	// Long-running fetches are handed to queue workers instead of holding a request open
+	startFetchJobWorkers(backgroundCtx, &app)
+
+	// Precompute item stats so queries never scan every stored match
+	startItemStatsScheduler(backgroundCtx, &app)
+
+	// Champion tier stats are computed from every participant of every ingested match
+	startChampionStatsScheduler(backgroundCtx, &app)
+
+	// Population percentiles for the summary's overall and role stats
+	startBenchmarkScheduler(backgroundCtx, &app)
+
 	r := chi.NewRouter()
 
 	r.Use(corsMiddleware)
+	r.Use(requestIDMiddleware)
 	r.Use(loggingMiddleware)
 
 	r.Route("/api", func(api chi.Router) {
@@ -434,31 +560,26 @@ func main() {
 		})
 
 		api.Get("/health", healthCheckHandler)
+		api.Get("/openapi.json", getOpenAPIHandler)
+		api.Get("/docs", getAPIDocsHandler)
 
-		// New consolidated dashboard endpoint that combines matches and summary
-		api.Get("/player/{region}/{gameName}/{tagLine}/dashboard", getPlayerDashboardHandler(&app))
-
-		// Legacy endpoints (kept for backward compatibility during transition)
-		api.Get("/player/{region}/{gameName}/{tagLine}/matches", getPlayerPerformanceHandler(&app))
-		api.Get("/player/{region}/{gameName}/{tagLine}/summary", getRecentGamesSummaryHandler(&app))
+		// GraphQL over players, matches and static data
+		graphQL := graphQLHandler(&app)
+		api.Get("/graphql", graphQL)
+		api.Post("/graphql", graphQL)
 
-		api.Get("/static-data", getStaticDataHandler(&app))
-		api.Get("/match/{region}/{matchId}", getMatchDetailsHandler(&app))
+		api.Get("/deprecations", getLegacyUsageHandler(&app))
 
-		api.Get("/popular-items", getPopularItemsHandler(&app))
+		// Unversioned routes are an alias for v1 so existing clients keep working
+		registerV1Routes(api, &app)
+		api.Route("/v1", func(v1 chi.Router) { registerV1Routes(v1, &app) })
+		api.Route("/v2", func(v2 chi.Router) { registerV2Routes(v2, &app) })
 	})
 
 	// Add a catch-all route for debugging 404s
 	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
 		log.Printf("404 Not Found: %s %s", r.Method, r.URL.Path)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error":   "Not Found",
-			"path":    r.URL.Path,
-			"method":  r.Method,
-			"message": "The requested endpoint does not exist",
-		})
+		writeAPIError(w, r, newAPIError(http.StatusNotFound, ErrCodeNotFound, fmt.Sprintf("The requested endpoint %s %s does not exist", r.Method, r.URL.Path)))
 	})
 
 	// Check if SSL should be enabled (default: true)
@@ -478,7 +599,7 @@ func main() {
 	}
 
 	srv := &http.Server{
-		Handler:      handlers.CompressHandler(r),
+		Handler:      compressMiddleware(r),
 		Addr:         ":" + port,
 		WriteTimeout: 15 * time.Second,
 		ReadTimeout:  15 * time.Second,
@@ -14,6 +14,16 @@ type AccountDTO struct {
 	TagLine  string `json:"tagLine"`
 }
 
+// LeagueEntryDTO represents one ranked queue entry from League-v4
+type LeagueEntryDTO struct {
+	QueueType    string `json:"queueType"` // e.g., "RANKED_SOLO_5x5"
+	Tier         string `json:"tier"`      // e.g., "GOLD"
+	Rank         string `json:"rank"`      // e.g., "II"
+	LeaguePoints int    `json:"leaguePoints"`
+	Wins         int    `json:"wins"`
+	Losses       int    `json:"losses"`
+}
+
 // MatchDto represents the Riot Match-v5 DTO (simplified)
 type MatchDto struct {
 	Metadata MatchMetadataDto `json:"metadata"`
@@ -38,8 +48,39 @@ type MatchInfoDto struct {
 	MapID            int              `json:"mapId"`
 	Participants     []ParticipantDto `json:"participants"`
 	QueueID          int              `json:"queueId"`
-	// Teams              []TeamDto            `json:"teams"` // We might need this later for win/loss
-	EndOfGameResult string `json:"endOfGameResult,omitempty"`
+	Teams            []TeamDto        `json:"teams"`
+	EndOfGameResult  string           `json:"endOfGameResult,omitempty"`
+}
+
+// TeamDto represents a team's result, bans and objectives in a match
+type TeamDto struct {
+	TeamID     int           `json:"teamId"`
+	Win        bool          `json:"win"`
+	Bans       []BanDto      `json:"bans"`
+	Objectives ObjectivesDto `json:"objectives"`
+}
+
+// BanDto represents a champion ban during champion select
+type BanDto struct {
+	ChampionID int `json:"championId"` // -1 when no ban was made
+	PickTurn   int `json:"pickTurn"`
+}
+
+// ObjectivesDto holds a team's objective counts
+type ObjectivesDto struct {
+	Baron      ObjectiveDto `json:"baron"`
+	Champion   ObjectiveDto `json:"champion"`
+	Dragon     ObjectiveDto `json:"dragon"`
+	Horde      ObjectiveDto `json:"horde"` // Void grubs
+	Inhibitor  ObjectiveDto `json:"inhibitor"`
+	RiftHerald ObjectiveDto `json:"riftHerald"`
+	Tower      ObjectiveDto `json:"tower"`
+}
+
+// ObjectiveDto holds first-take and kill count for a single objective
+type ObjectiveDto struct {
+	First bool `json:"first"`
+	Kills int  `json:"kills"`
 }
 
 // ParticipantDto represents a participant in a match (simplified)
@@ -82,12 +123,26 @@ type ParticipantDto struct {
 	TotalDamageDealtToChampions int                       `json:"totalDamageDealtToChampions"`
 	TotalDamageTaken            int                       `json:"totalDamageTaken"`
 	TimePlayed                  int                       `json:"timePlayed"`
+
+	TotalHealsOnTeammates          int `json:"totalHealsOnTeammates"`
+	TotalDamageShieldedOnTeammates int `json:"totalDamageShieldedOnTeammates"`
+
+	// Arena (CHERRY) only: duos share a subteam, placed 1-8 at the end of the game
+	PlayerSubteamID  int `json:"playerSubteamId"`
+	SubteamPlacement int `json:"subteamPlacement"`
+	PlayerAugment1   int `json:"playerAugment1"`
+	PlayerAugment2   int `json:"playerAugment2"`
+	PlayerAugment3   int `json:"playerAugment3"`
+	PlayerAugment4   int `json:"playerAugment4"`
+	PlayerAugment5   int `json:"playerAugment5"`
+	PlayerAugment6   int `json:"playerAugment6"`
 }
 
 // ParticipantChallengesDto holds specific challenge data if needed
 type ParticipantChallengesDto struct {
 	KDA               float64 `json:"kda,omitempty"`
 	KillParticipation float64 `json:"killParticipation,omitempty"`
+	SnowballsHit      int     `json:"snowballsHit,omitempty"` // ARAM Mark/Dash hits
 	// Add other challenges as needed
 }
 
@@ -139,6 +194,7 @@ type PlayerMatchStats struct {
 	TeamPosition       string        `json:"teamPosition" bson:"teamPosition"`
 	Items              []int         `json:"items" bson:"items"`
 	SummonerSpells     []int         `json:"summonerSpells" bson:"summonerSpells"`
+	SummonerSpellCasts []int         `json:"summonerSpellCasts,omitempty" bson:"summonerSpellCasts,omitempty"` // Casts per slot, same order as SummonerSpells
 	PrimaryRune        int           `json:"primaryRune" bson:"primaryRune"`
 	SecondaryStyle     int           `json:"secondaryStyle" bson:"secondaryStyle"`
 	ChampLevel         int           `json:"champLevel" bson:"champLevel"`
@@ -146,9 +202,44 @@ type PlayerMatchStats struct {
 	DamageToObjectives int           `json:"damageToObjectives" bson:"damageToObjectives"`
 	DamageToChampions  int           `json:"damageToChampions" bson:"damageToChampions"`
 	TotalDamageTaken   int           `json:"totalDamageTaken" bson:"totalDamageTaken"`
+	HealsOnTeammates   int           `json:"healsOnTeammates" bson:"healsOnTeammates"`
+	ShieldsOnTeammates int           `json:"shieldsOnTeammates" bson:"shieldsOnTeammates"`
 	TeamID             int           `json:"teamId" bson:"teamId"` // 100 for blue, 200 for red
 	QueueID            int           `json:"queueId" bson:"queueId"`
+	Patch              string        `json:"patch" bson:"patch"`
+	RunePage           *RunePage     `json:"runePage,omitempty" bson:"runePage,omitempty"`
+	PerformanceScore   float64       `json:"performanceScore" bson:"performanceScore"`
+	PerformanceGrade   string        `json:"performanceGrade,omitempty" bson:"performanceGrade,omitempty"`
+	PerformanceBadge   string        `json:"performanceBadge,omitempty" bson:"performanceBadge,omitempty"`
 	FullMatchData      *MatchInfoDto `json:"-" bson:"-"` // To hold the original match data if needed for more processing, but not sent to frontend directly for this summary
+
+	// Mode-specific fields, nil for every other mode
+	Arena *ArenaMatchStats `json:"arena,omitempty" bson:"arena,omitempty"`
+	Aram  *AramMatchStats  `json:"aram,omitempty" bson:"aram,omitempty"`
+}
+
+// AramMatchStats holds the ARAM specific part of a player's match
+type AramMatchStats struct {
+	SnowballsThrown int `json:"snowballsThrown" bson:"snowballsThrown"` // Mark casts
+	SnowballsHit    int `json:"snowballsHit" bson:"snowballsHit"`
+}
+
+// ArenaMatchStats holds the Arena (CHERRY) specific part of a player's match
+type ArenaMatchStats struct {
+	SubteamID           int    `json:"subteamId" bson:"subteamId"`
+	Placement           int    `json:"placement" bson:"placement"` // 1-8
+	Augments            []int  `json:"augments" bson:"augments"`   // Picked augments in slot order, empty slots left out
+	PartnerPUUID        string `json:"partnerPuuid,omitempty" bson:"partnerPuuid,omitempty"`
+	PartnerChampionID   int    `json:"partnerChampionId,omitempty" bson:"partnerChampionId,omitempty"`
+	PartnerChampionName string `json:"partnerChampionName,omitempty" bson:"partnerChampionName,omitempty"`
+}
+
+// RunePage is the full rune page a player used in a match
+type RunePage struct {
+	PrimaryStyle int          `json:"primaryStyle" bson:"primaryStyle"`
+	SubStyle     int          `json:"subStyle" bson:"subStyle"`
+	Perks        []int        `json:"perks" bson:"perks"` // Primary selections (keystone first), then secondary selections
+	StatPerks    StatPerksDto `json:"statPerks" bson:"statPerks"`
 }
 
 // UserPerformance stores a collection of match stats for a user
@@ -157,6 +248,7 @@ type UserPerformance struct {
 	Region    string             `json:"region" bson:"region"`
 	RiotID    string             `json:"riotId" bson:"riotId"` // GameName#TagLine
 	Matches   []PlayerMatchStats `json:"matches" bson:"matches"`
+	RankTier  string             `json:"rankTier,omitempty" bson:"rankTier,omitempty"` // Solo queue tier (flex if unranked in solo), empty when unknown
 	UpdatedAt int64              `json:"updatedAt" bson:"updatedAt"`
 }
 
@@ -202,6 +294,8 @@ type ItemData struct {
 	Maps        map[string]bool    `json:"maps"`
 	Stats       map[string]float64 `json:"stats"`
 	Depth       int                `json:"depth,omitempty"` // For component items
+	From        []string           `json:"from,omitempty"`  // Component item IDs
+	Into        []string           `json:"into,omitempty"`  // Item IDs this builds into
 }
 
 // ItemImageDTO for Data Dragon items
@@ -294,18 +388,65 @@ type DataDragonSummonerSpells struct {
 	Data    map[string]SummonerSpellData `json:"data"` // Keyed by spell ID (e.g., "SummonerFlash")
 }
 
+// ArenaAugmentData is one augment from CommunityDragon's Arena data set
+type ArenaAugmentData struct {
+	ID        int    `json:"id"`
+	APIName   string `json:"apiName"`
+	Name      string `json:"name"`
+	Desc      string `json:"desc"` // Contains HTML-like tags and @variables@
+	IconLarge string `json:"iconLarge"`
+	IconSmall string `json:"iconSmall"`
+	Rarity    int    `json:"rarity"` // 0 silver, 1 gold, 2 prismatic
+}
+
+// CommunityDragonArena holds the Arena data set from CommunityDragon
+type CommunityDragonArena struct {
+	Augments []ArenaAugmentData `json:"augments"`
+}
+
 // DataDragonVersions lists available Data Dragon versions
 type DataDragonVersions []string
 
+// QueueDto is one entry of Riot's static queues.json
+type QueueDto struct {
+	QueueID     int    `json:"queueId"`
+	Map         string `json:"map"`
+	Description string `json:"description"` // e.g., "5v5 Ranked Solo games"; null for custom games
+	Notes       string `json:"notes"`
+}
+
+// QueueInfo describes a queue from the queue catalog
+type QueueInfo struct {
+	QueueID    int    `json:"queueId" bson:"queueId"`
+	Name       string `json:"name" bson:"name"` // e.g., "Ranked Solo/Duo"
+	Map        string `json:"map" bson:"map"`
+	Ranked     bool   `json:"ranked" bson:"ranked"`
+	Deprecated bool   `json:"deprecated" bson:"deprecated"`
+}
+
 // StaticData holds all loaded static data (champions, items, etc.)
 type StaticData struct {
 	Champions      map[string]ChampionData      // Keyed by Champion Key (string version of ID)
 	Items          map[string]ItemData          // Keyed by Item ID (string)
 	Runes          map[int]RuneInfo             // Keyed by Rune ID (int)
+	RunePaths      map[int]RunePathData         // Keyed by rune tree ID (int)
 	SummonerSpells map[string]SummonerSpellData // Keyed by Summoner Spell Key (string version of ID)
+	Queues         map[int]QueueInfo            // Keyed by queue ID (int)
+	Augments       map[int]ArenaAugmentData     // Keyed by Arena augment ID (int)
 	LatestVersion  string
 }
 
+// StaticDataResponse is the /api/static-data payload
+type StaticDataResponse struct {
+	Champions      map[string]ChampionData      `json:"champions"`
+	Items          map[string]ItemData          `json:"items"`
+	Runes          map[int]RuneInfo             `json:"runes"`
+	SummonerSpells map[string]SummonerSpellData `json:"summonerSpells"`
+	Queues         map[int]QueueInfo            `json:"queues"`
+	Augments       map[int]ArenaAugmentData     `json:"augments"`
+	LatestVersion  string                       `json:"latestVersion"`
+}
+
 // GlobalAppData holds clients and other global resources
 type GlobalAppData struct {
 	httpClient    *http.Client
@@ -324,6 +465,10 @@ type RecentGamesSummary struct {
 	OverallStats  OverallStats             `json:"overallStats" bson:"overallStats"`
 	RoleStats     map[string]RoleStats     `json:"roleStats" bson:"roleStats"`
 	ChampionStats map[string]ChampionStats `json:"championStats" bson:"championStats"`
+	SpellStats    *SummonerSpellSummary    `json:"spellStats,omitempty" bson:"spellStats,omitempty"`
+	QueueStats    map[string]QueueStats    `json:"queueStats" bson:"queueStats"` // Keyed by queue ID (string)
+	ArenaStats    *ArenaSummary            `json:"arenaStats,omitempty" bson:"arenaStats,omitempty"`
+	AramStats     *AramSummary             `json:"aramStats,omitempty" bson:"aramStats,omitempty"`
 	RecentMatches []PlayerMatchStats       `json:"recentMatches" bson:"recentMatches"`
 	LastUpdated   int64                    `json:"lastUpdated" bson:"lastUpdated"`
 }
@@ -346,6 +491,9 @@ type OverallStats struct {
 	AvgGoldPerMin        float64 `json:"avgGoldPerMin" bson:"avgGoldPerMin"`
 	AvgDamageToChampions float64 `json:"avgDamageToChampions" bson:"avgDamageToChampions"`
 	AvgKillParticipation float64 `json:"avgKillParticipation" bson:"avgKillParticipation"`
+
+	// Where the player sits in the stored population, filled in by annotatePercentiles
+	Percentiles *PercentileStats `json:"percentiles,omitempty" bson:"percentiles,omitempty"`
 }
 
 type RoleStats struct {
@@ -366,6 +514,86 @@ type RoleStats struct {
 	AvgGoldPerMin        float64 `json:"avgGoldPerMin" bson:"avgGoldPerMin"`
 	AvgDamageToChampions float64 `json:"avgDamageToChampions" bson:"avgDamageToChampions"`
 	AvgKillParticipation float64 `json:"avgKillParticipation" bson:"avgKillParticipation"`
+	AvgPerformanceScore  float64 `json:"avgPerformanceScore" bson:"avgPerformanceScore"`
+	MVPCount             int     `json:"mvpCount" bson:"mvpCount"`
+	ACECount             int     `json:"aceCount" bson:"aceCount"`
+
+	// Where the player sits in the stored population, filled in by annotatePercentiles
+	Percentiles *PercentileStats `json:"percentiles,omitempty" bson:"percentiles,omitempty"`
+}
+
+// ArenaAugmentStats holds results for one augment across a player's Arena games
+type ArenaAugmentStats struct {
+	Augment      StaticRef `json:"augment" bson:"augment"`
+	Games        int       `json:"games" bson:"games"`
+	FirstPlaces  int       `json:"firstPlaces" bson:"firstPlaces"`
+	Top4         int       `json:"top4" bson:"top4"`
+	WinRate      float64   `json:"winRate" bson:"winRate"`   // Share of games placed first
+	Top4Rate     float64   `json:"top4Rate" bson:"top4Rate"` // Share of games placed in the top 4
+	AvgPlacement float64   `json:"avgPlacement" bson:"avgPlacement"`
+}
+
+// ArenaDuoStats holds results for one pairing of the player's champion and their partner's
+type ArenaDuoStats struct {
+	Champion        StaticRef `json:"champion" bson:"champion"`
+	PartnerChampion StaticRef `json:"partnerChampion" bson:"partnerChampion"`
+	Games           int       `json:"games" bson:"games"`
+	FirstPlaces     int       `json:"firstPlaces" bson:"firstPlaces"`
+	Top4            int       `json:"top4" bson:"top4"`
+	WinRate         float64   `json:"winRate" bson:"winRate"`
+	Top4Rate        float64   `json:"top4Rate" bson:"top4Rate"`
+	AvgPlacement    float64   `json:"avgPlacement" bson:"avgPlacement"`
+}
+
+// ArenaSummary aggregates a player's Arena games by placement rather than Summoner's Rift stats
+type ArenaSummary struct {
+	Games        int                 `json:"games" bson:"games"`
+	FirstPlaces  int                 `json:"firstPlaces" bson:"firstPlaces"`
+	Top4         int                 `json:"top4" bson:"top4"`
+	WinRate      float64             `json:"winRate" bson:"winRate"` // Share of games placed first
+	Top4Rate     float64             `json:"top4Rate" bson:"top4Rate"`
+	AvgPlacement float64             `json:"avgPlacement" bson:"avgPlacement"`
+	Augments     []ArenaAugmentStats `json:"augments" bson:"augments"` // Most picked first
+	Duos         []ArenaDuoStats     `json:"duos" bson:"duos"`         // Most played first
+}
+
+// AramRates are the per-minute rates compared between ARAM and Summoner's Rift
+type AramRates struct {
+	Games                       int     `json:"games" bson:"games"`
+	DamagePerMin                float64 `json:"damagePerMin" bson:"damagePerMin"`
+	HealShieldOnTeammatesPerMin float64 `json:"healShieldOnTeammatesPerMin" bson:"healShieldOnTeammatesPerMin"`
+	DeathsPerMin                float64 `json:"deathsPerMin" bson:"deathsPerMin"`
+}
+
+// AramChampionStats holds a player's ARAM results on one champion
+type AramChampionStats struct {
+	Champion StaticRef `json:"champion" bson:"champion"`
+	Games    int       `json:"games" bson:"games"`
+	Wins     int       `json:"wins" bson:"wins"`
+	WinRate  float64   `json:"winRate" bson:"winRate"`
+}
+
+// AramSummary holds ARAM-specific analytics, compared against the player's own Summoner's Rift
+// games and, once annotatePercentiles has run, against stored ARAM players
+type AramSummary struct {
+	Games               int                 `json:"games" bson:"games"`
+	Wins                int                 `json:"wins" bson:"wins"`
+	WinRate             float64             `json:"winRate" bson:"winRate"`
+	Rates               AramRates           `json:"rates" bson:"rates"`
+	RiftRates           *AramRates          `json:"riftRates,omitempty" bson:"riftRates,omitempty"` // Nil without classic games to compare to
+	SnowballsThrown     int                 `json:"snowballsThrown" bson:"snowballsThrown"`
+	SnowballsHit        int                 `json:"snowballsHit" bson:"snowballsHit"`
+	SnowballHitRate     float64             `json:"snowballHitRate" bson:"snowballHitRate"`
+	SnowballHitsPerGame float64             `json:"snowballHitsPerGame" bson:"snowballHitsPerGame"`
+	Champions           []AramChampionStats `json:"champions" bson:"champions"` // Most played first
+	Percentiles         *PercentileStats    `json:"percentiles,omitempty" bson:"percentiles,omitempty"`
+}
+
+// QueueStats holds a player's stats for one queue
+type QueueStats struct {
+	Queue       QueueInfo    `json:"queue" bson:"queue"`
+	GamesPlayed int          `json:"gamesPlayed" bson:"gamesPlayed"`
+	Stats       OverallStats `json:"stats" bson:"stats"`
 }
 
 type ChampionStats struct {
@@ -389,6 +617,9 @@ type ChampionStats struct {
 	AvgGoldPerMin        float64 `json:"avgGoldPerMin" bson:"avgGoldPerMin"`
 	AvgDamageToChampions float64 `json:"avgDamageToChampions" bson:"avgDamageToChampions"`
 	AvgKillParticipation float64 `json:"avgKillParticipation" bson:"avgKillParticipation"`
+	AvgPerformanceScore  float64 `json:"avgPerformanceScore" bson:"avgPerformanceScore"`
+	MVPCount             int     `json:"mvpCount" bson:"mvpCount"`
+	ACECount             int     `json:"aceCount" bson:"aceCount"`
 	LastPlayed           int64   `json:"lastPlayed" bson:"lastPlayed"`
 }
 
@@ -469,4 +700,665 @@ type PaginatedDashboardResponse struct {
 	Matches          []PlayerMatchStats  `json:"matches"`
 	Pagination       PaginationInfo      `json:"pagination"`
 	IncrementalStats *IncrementalStats   `json:"incrementalStats"`
+	Sessions         *SessionAnalysis    `json:"sessions,omitempty"` // First page only
+
+	// Identify the page's data for ETags; not sent to clients
+	LatestMatchID string `json:"-"`
+	UpdatedAt     int64  `json:"-"`
+}
+
+// BatchPlayersRequest is the body of POST /api/players/batch
+type BatchPlayersRequest struct {
+	Players []TrackPlayerRequest `json:"players"`           // Riot IDs to look up, at most 20
+	Count   int                  `json:"count,omitempty"`   // Matches per player, default 20
+	QueueID int                  `json:"queueId,omitempty"` // 0 for all queues
+}
+
+// PlayerOverview is the lightweight per-player summary returned by the batch lookup
+type PlayerOverview struct {
+	TotalMatches int             `json:"totalMatches"`
+	OverallStats OverallStats    `json:"overallStats"`
+	MainRole     string          `json:"mainRole,omitempty"` // Most played role, empty without matches
+	TopChampions []ChampionStats `json:"topChampions"`       // Most played first, at most 3
+	RankTier     string          `json:"rankTier,omitempty"`
+	LastUpdated  int64           `json:"lastUpdated"`
+}
+
+// BatchPlayerResult is one entry of a batch lookup, in request order. Exactly one of Overview
+// and Error is set.
+type BatchPlayerResult struct {
+	Region   string          `json:"region"`
+	GameName string          `json:"gameName"`
+	TagLine  string          `json:"tagLine"`
+	PUUID    string          `json:"puuid,omitempty"`
+	Overview *PlayerOverview `json:"overview,omitempty"`
+	Error    *ErrorBody      `json:"error,omitempty"`
+}
+
+// BatchPlayersResponse is the POST /api/players/batch payload
+type BatchPlayersResponse struct {
+	Results   []BatchPlayerResult `json:"results"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+}
+
+// RiotIDRecord is one Riot ID a player has been seen under. Times are Unix seconds.
+type RiotIDRecord struct {
+	Key       string `json:"-" bson:"key"` // Lowercase gameName#tagLine, Riot IDs are case-insensitive
+	GameName  string `json:"gameName" bson:"gameName"`
+	TagLine   string `json:"tagLine" bson:"tagLine"`
+	FirstSeen int64  `json:"firstSeen" bson:"firstSeen"`
+	LastSeen  int64  `json:"lastSeen" bson:"lastSeen"`
+}
+
+// NameHistory is the namehistory document for one PUUID
+type NameHistory struct {
+	PUUID      string         `bson:"_id"`
+	GameName   string         `bson:"gameName"` // Current Riot ID
+	TagLine    string         `bson:"tagLine"`
+	CurrentKey string         `bson:"currentKey"`
+	SeenAt     int64          `bson:"seenAt"` // When the current Riot ID was last confirmed
+	Names      []RiotIDRecord `bson:"names"`
+}
+
+// PlayerAccount is the GET /api/player/by-puuid/{region}/{puuid} payload
+type PlayerAccount struct {
+	PUUID       string         `json:"puuid"`
+	Region      string         `json:"region"`
+	GameName    string         `json:"gameName"`
+	TagLine     string         `json:"tagLine"`
+	RiotID      string         `json:"riotId"`
+	NameHistory []RiotIDRecord `json:"nameHistory"` // Most recently seen first, including the current Riot ID
+}
+
+// SearchEntry is the searchindex document for one player in one region
+type SearchEntry struct {
+	ID        string   `bson:"_id"` // {region}:{puuid}
+	PUUID     string   `bson:"puuid"`
+	Region    string   `bson:"region"`
+	GameName  string   `bson:"gameName"`
+	TagLine   string   `bson:"tagLine"`
+	SearchKey string   `bson:"searchKey"` // Lowercase, accent-free gameName#tagLine
+	Words     []string `bson:"words"`     // Folded game name from each later word on, for matches mid-name
+	LastSeen  int64    `bson:"lastSeen"`  // Unix seconds of the newest match the Riot ID was seen in
+}
+
+// SearchSuggestion is one known Riot ID suggested by GET /api/search
+type SearchSuggestion struct {
+	GameName string `json:"gameName"`
+	TagLine  string `json:"tagLine"`
+	RiotID   string `json:"riotId"`
+	Region   string `json:"region"`
+	LastSeen int64  `json:"lastSeen"` // Unix seconds of the newest match the Riot ID was seen in
+}
+
+// SearchResponse is the GET /api/search payload
+type SearchResponse struct {
+	Query       string             `json:"query"`
+	Suggestions []SearchSuggestion `json:"suggestions"` // Best match first
+}
+
+// MatchV2 is a PlayerMatchStats with its IDs resolved against static data
+type MatchV2 struct {
+	PlayerMatchStats
+	Role          string      `json:"role"`
+	Queue         QueueInfo   `json:"queue"`
+	Champion      StaticRef   `json:"champion"`
+	ItemRefs      []StaticRef `json:"itemRefs"`
+	SpellRefs     []StaticRef `json:"spellRefs"`
+	Keystone      *StaticRef  `json:"keystone,omitempty"`
+	SecondaryTree *StaticRef  `json:"secondaryTree,omitempty"`
+	AugmentRefs   []StaticRef `json:"augmentRefs,omitempty"` // Arena only
+}
+
+// DashboardResponseV2 is the v2 dashboard: the v1 payload with matches resolved to MatchV2
+type DashboardResponseV2 struct {
+	APIVersion       string              `json:"apiVersion"`
+	Summary          *RecentGamesSummary `json:"summary"`
+	Matches          []MatchV2           `json:"matches"`
+	Pagination       PaginationInfo      `json:"pagination"`
+	IncrementalStats *IncrementalStats   `json:"incrementalStats"`
+	Sessions         *SessionAnalysis    `json:"sessions,omitempty"` // First page only
+}
+
+// LegacyRouteUsage counts calls to a deprecated route per day
+type LegacyRouteUsage struct {
+	Route   string           `json:"route"`
+	Sunset  string           `json:"sunset"` // HTTP date after which the route may be removed
+	Total   int64            `json:"total"`
+	PerDay  map[string]int64 `json:"perDay"`            // Keyed by YYYY-MM-DD (UTC)
+	LastDay string           `json:"lastDay,omitempty"` // Most recent day with any calls
+}
+
+// TrackedPlayer is a player whose match data is refreshed in the background on a schedule
+type TrackedPlayer struct {
+	ID              string `json:"id" bson:"_id"` // region_puuid, same key format as the user performance cache
+	PUUID           string `json:"puuid" bson:"puuid"`
+	Region          string `json:"region" bson:"region"`
+	GameName        string `json:"gameName" bson:"gameName"`
+	TagLine         string `json:"tagLine" bson:"tagLine"`
+	AddedAt         int64  `json:"addedAt" bson:"addedAt"`
+	LastRefreshedAt int64  `json:"lastRefreshedAt" bson:"lastRefreshedAt"`
+	NextRefreshAt   int64  `json:"nextRefreshAt" bson:"nextRefreshAt"`
+	LastError       string `json:"lastError,omitempty" bson:"lastError,omitempty"`
+}
+
+// TrackPlayerRequest is the request body for adding a tracked player
+type TrackPlayerRequest struct {
+	Region   string `json:"region"`
+	GameName string `json:"gameName"`
+	TagLine  string `json:"tagLine"`
+}
+
+// FetchJobRequest is the request body for queueing a background match fetch
+type FetchJobRequest struct {
+	Region   string `json:"region"`
+	GameName string `json:"gameName"`
+	TagLine  string `json:"tagLine"`
+	Count    int    `json:"count"`
+	QueueID  int    `json:"queueId"`
+}
+
+// FetchJob tracks a background match fetch and its progress.
+// Jobs are stored as Redis hashes, hence the redis tags.
+type FetchJob struct {
+	ID        string `json:"jobId" redis:"id"`
+	Status    string `json:"status" redis:"status"` // queued, running, completed or failed
+	Region    string `json:"region" redis:"region"`
+	GameName  string `json:"gameName" redis:"gameName"`
+	TagLine   string `json:"tagLine" redis:"tagLine"`
+	Count     int    `json:"count" redis:"count"`
+	QueueID   int    `json:"queueId" redis:"queueId"`
+	Total     int    `json:"total" redis:"total"`
+	Fetched   int    `json:"fetched" redis:"fetched"`
+	Failed    int    `json:"failed" redis:"failed"`
+	Error     string `json:"error,omitempty" redis:"error"`
+	CreatedAt int64  `json:"createdAt" redis:"createdAt"`
+	UpdatedAt int64  `json:"updatedAt" redis:"updatedAt"`
+
+	Matches []PlayerMatchStats `json:"matches,omitempty" redis:"-"` // Result of a completed job with a queueId, newest first
+}
+
+// DashboardStreamMeta is the first event of a dashboard stream
+type DashboardStreamMeta struct {
+	PUUID        string `json:"puuid"`
+	Region       string `json:"region"`
+	RiotID       string `json:"riotId"`
+	TotalMatches int    `json:"totalMatches"`
+}
+
+// StaticRef is a static data entry resolved to its display name and Data Dragon image URL
+type StaticRef struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Image string `json:"image,omitempty"`
+}
+
+// ScoreboardParticipant is one player's row on a match scoreboard
+type ScoreboardParticipant struct {
+	PlayerMatchStats
+	PUUID          string      `json:"puuid"`
+	RiotIDGameName string      `json:"riotIdGameName"`
+	RiotIDTagline  string      `json:"riotIdTagline"`
+	Champion       StaticRef   `json:"champion"`
+	ItemRefs       []StaticRef `json:"itemRefs"`
+	SpellRefs      []StaticRef `json:"summonerSpellRefs"`
+	PrimaryRuneRef *StaticRef  `json:"primaryRuneRef,omitempty"`
+	DamageShare    float64     `json:"damageShare"` // Share of the team's damage to champions, 0-1
+	GoldShare      float64     `json:"goldShare"`   // Share of the team's gold earned, 0-1
+}
+
+// ScoreboardTeam holds a team's totals and participants
+type ScoreboardTeam struct {
+	TeamID             int                     `json:"teamId"`
+	Win                bool                    `json:"win"`
+	Kills              int                     `json:"kills"`
+	Deaths             int                     `json:"deaths"`
+	Assists            int                     `json:"assists"`
+	GoldEarned         int                     `json:"goldEarned"`
+	DamageToChampions  int                     `json:"damageToChampions"`
+	TotalMinionsKilled int                     `json:"totalMinionsKilled"`
+	VisionScore        int                     `json:"visionScore"`
+	Objectives         *ObjectivesDto          `json:"objectives,omitempty"`
+	Bans               []StaticRef             `json:"bans"`
+	Participants       []ScoreboardParticipant `json:"participants"`
+}
+
+// MatchScoreboard is the normalized view of a match with every participant
+type MatchScoreboard struct {
+	MatchID      string           `json:"matchId"`
+	GameMode     string           `json:"gameMode"`
+	GameVersion  string           `json:"gameVersion"`
+	GameCreation int64            `json:"gameCreation"`
+	GameDuration int64            `json:"gameDuration"`
+	QueueID      int              `json:"queueId"`
+	Teams        []ScoreboardTeam `json:"teams"`
+}
+
+// MatchTimelineDto represents the Riot Match-v5 timeline DTO (simplified)
+type MatchTimelineDto struct {
+	Metadata MatchMetadataDto     `json:"metadata"`
+	Info     MatchTimelineInfoDto `json:"info"`
+}
+
+// MatchTimelineInfoDto holds the timeline frames and participant mapping
+type MatchTimelineInfoDto struct {
+	FrameInterval int64                      `json:"frameInterval"`
+	Frames        []TimelineFrameDto         `json:"frames"`
+	Participants  []TimelineParticipantIDDto `json:"participants"`
+}
+
+// TimelineParticipantIDDto maps a timeline participant ID (1-10) to a PUUID
+type TimelineParticipantIDDto struct {
+	ParticipantID int    `json:"participantId"`
+	PUUID         string `json:"puuid"`
+}
+
+// TimelineFrameDto is one frame (usually a minute) of the timeline
+type TimelineFrameDto struct {
+	Timestamp int64              `json:"timestamp"`
+	Events    []TimelineEventDto `json:"events"`
+}
+
+// TimelineEventDto is a single timeline event; only the fields we use are decoded
+type TimelineEventDto struct {
+	Type          string `json:"type"` // e.g. ITEM_PURCHASED, ITEM_SOLD, ITEM_UNDO, SKILL_LEVEL_UP
+	Timestamp     int64  `json:"timestamp"`
+	ParticipantID int    `json:"participantId"`
+	ItemID        int    `json:"itemId,omitempty"`
+	BeforeID      int    `json:"beforeId,omitempty"`  // ITEM_UNDO: item removed by the undo
+	AfterID       int    `json:"afterId,omitempty"`   // ITEM_UNDO: item restored by the undo
+	SkillSlot     int    `json:"skillSlot,omitempty"` // 1=Q, 2=W, 3=E, 4=R
+	LevelUpType   string `json:"levelUpType,omitempty"`
+}
+
+// ItemEvent is one step of a player's build path
+type ItemEvent struct {
+	ItemID    int    `json:"itemId"`
+	Timestamp int64  `json:"timestamp"` // Milliseconds since game start
+	Action    string `json:"action"`    // purchased or sold
+}
+
+// MatchBuild is a player's item build path and skill order for a single match
+type MatchBuild struct {
+	MatchID       string      `json:"matchId"`
+	ChampionName  string      `json:"championName"`
+	ChampionID    int         `json:"championId"`
+	Win           bool        `json:"win"`
+	GameCreation  int64       `json:"gameCreation"`
+	BuildPath     []ItemEvent `json:"buildPath"`
+	CoreItems     []int       `json:"coreItems"`     // First completed non-boot items in purchase order
+	SkillOrder    []int       `json:"skillOrder"`    // Skill slot leveled at each level
+	SkillMaxOrder string      `json:"skillMaxOrder"` // e.g. "QEW", empty if too few levels to tell
+}
+
+// CoreBuildStats aggregates games played with the same core build
+type CoreBuildStats struct {
+	Items   []StaticRef `json:"items"`
+	Games   int         `json:"games"`
+	Wins    int         `json:"wins"`
+	WinRate float64     `json:"winRate"`
+}
+
+// SkillOrderStats aggregates games played with the same skill max order
+type SkillOrderStats struct {
+	SkillMaxOrder string  `json:"skillMaxOrder"`
+	Games         int     `json:"games"`
+	Wins          int     `json:"wins"`
+	WinRate       float64 `json:"winRate"`
+}
+
+// ChampionBuildStats holds a player's builds on one champion, most common first
+type ChampionBuildStats struct {
+	ChampionName  string            `json:"championName"`
+	ChampionID    int               `json:"championId"`
+	GamesAnalyzed int               `json:"gamesAnalyzed"`
+	CoreBuilds    []CoreBuildStats  `json:"coreBuilds"`
+	SkillOrders   []SkillOrderStats `json:"skillOrders"`
+}
+
+// PlayerBuildsResponse is the response for a player's build and skill order analysis
+type PlayerBuildsResponse struct {
+	PUUID     string                        `json:"puuid"`
+	Region    string                        `json:"region"`
+	RiotID    string                        `json:"riotId"`
+	Matches   []MatchBuild                  `json:"matches"`
+	Champions map[string]ChampionBuildStats `json:"champions"`
+}
+
+// ItemStatsBucket holds precomputed item counts for one champion, role, patch and queue
+type ItemStatsBucket struct {
+	ID           string            `bson:"_id"` // championId|role|patch|queueId
+	ChampionID   int               `bson:"championId"`
+	ChampionName string            `bson:"championName"`
+	Role         string            `bson:"role"`
+	Patch        string            `bson:"patch"`
+	QueueID      int               `bson:"queueId"`
+	Games        int               `bson:"games"`
+	Wins         int               `bson:"wins"`
+	Items        []ItemStatsCounts `bson:"items"`
+	UpdatedAt    int64             `bson:"updatedAt"`
+}
+
+// ItemStatsCounts holds how often an item was finished in a bucket's games
+type ItemStatsCounts struct {
+	ItemID int `bson:"itemId"`
+	Picks  int `bson:"picks"`
+	Wins   int `bson:"wins"`
+}
+
+// ItemStatsFilters echoes the filters applied to an item stats query
+type ItemStatsFilters struct {
+	ChampionID int    `json:"championId,omitempty"`
+	Role       string `json:"role,omitempty"`
+	Patch      string `json:"patch,omitempty"`
+	QueueID    int    `json:"queueId,omitempty"`
+}
+
+// ItemStatsEntry is one completed item's pick and win rates
+type ItemStatsEntry struct {
+	Item     StaticRef `json:"item"`
+	Tags     []string  `json:"tags"`
+	Depth    int       `json:"depth"`
+	Gold     int       `json:"gold"`
+	Picks    int       `json:"picks"`
+	Wins     int       `json:"wins"`
+	PickRate float64   `json:"pickRate"` // Percentage of sampled games with the item finished
+	WinRate  float64   `json:"winRate"`
+}
+
+// ItemStatsResponse is the response for the item stats endpoint
+type ItemStatsResponse struct {
+	Filters     ItemStatsFilters `json:"filters"`
+	Games       int              `json:"games"`
+	Items       []ItemStatsEntry `json:"items"`
+	GeneratedAt int64            `json:"generatedAt"`
+}
+
+// IngestedMatch is the compact form of a full match kept for population-wide statistics
+type IngestedMatch struct {
+	MatchID      string                `bson:"_id"`
+	Region       string                `bson:"region"`
+	Patch        string                `bson:"patch"`
+	QueueID      int                   `bson:"queueId"`
+	GameMode     string                `bson:"gameMode"`
+	GameCreation int64                 `bson:"gameCreation"`
+	GameDuration int64                 `bson:"gameDuration"`
+	Bans         []int                 `bson:"bans"` // Champion IDs banned by either team
+	Participants []IngestedParticipant `bson:"participants"`
+	IngestedAt   int64                 `bson:"ingestedAt"`
+}
+
+// IngestedParticipant is one participant's row in an IngestedMatch
+type IngestedParticipant struct {
+	PUUID        string `bson:"puuid"`
+	ChampionID   int    `bson:"championId"`
+	ChampionName string `bson:"championName"`
+	Role         string `bson:"role"` // As returned by normalizeRole
+	TeamID       int    `bson:"teamId"`
+	Win          bool   `bson:"win"`
+	Kills        int    `bson:"kills"`
+	Deaths       int    `bson:"deaths"`
+	Assists      int    `bson:"assists"`
+}
+
+// ChampionStatsBucket holds precomputed champion statistics.
+// Kind "pick" buckets are per champion, role, patch and queue; "ban" buckets are per champion,
+// patch and queue; "total" buckets count matches per patch and queue.
+type ChampionStatsBucket struct {
+	ID           string `bson:"_id"`
+	Kind         string `bson:"kind"`
+	ChampionID   int    `bson:"championId,omitempty"`
+	ChampionName string `bson:"championName,omitempty"`
+	Role         string `bson:"role,omitempty"`
+	Patch        string `bson:"patch"`
+	QueueID      int    `bson:"queueId"`
+	Count        int    `bson:"count"` // Games picked, times banned or matches, depending on kind
+	Wins         int    `bson:"wins,omitempty"`
+	Kills        int    `bson:"kills,omitempty"`
+	Deaths       int    `bson:"deaths,omitempty"`
+	Assists      int    `bson:"assists,omitempty"`
+	UpdatedAt    int64  `bson:"updatedAt"`
+}
+
+// ChampionTierFilters echoes the filters applied to a champion stats query
+type ChampionTierFilters struct {
+	ChampionID int    `json:"championId,omitempty"`
+	Role       string `json:"role,omitempty"`
+	Patch      string `json:"patch,omitempty"`
+	QueueID    int    `json:"queueId,omitempty"`
+	MinGames   int    `json:"minGames"`
+}
+
+// ChampionTierEntry is one champion and role's population statistics
+type ChampionTierEntry struct {
+	Champion   StaticRef `json:"champion"`
+	Role       string    `json:"role"`
+	Games      int       `json:"games"`
+	Wins       int       `json:"wins"`
+	WinRate    float64   `json:"winRate"`
+	PickRate   float64   `json:"pickRate"`
+	BanRate    float64   `json:"banRate"`
+	AvgKills   float64   `json:"avgKills"`
+	AvgDeaths  float64   `json:"avgDeaths"`
+	AvgAssists float64   `json:"avgAssists"`
+	AvgKDA     float64   `json:"avgKDA"`
+}
+
+// ChampionTierResponse is the response for the champion stats endpoint
+type ChampionTierResponse struct {
+	Filters     ChampionTierFilters `json:"filters"`
+	Matches     int                 `json:"matches"`
+	Champions   []ChampionTierEntry `json:"champions"`
+	GeneratedAt int64               `json:"generatedAt"`
+}
+
+// RunePageStats is one distinct rune page and how it performed
+type RunePageStats struct {
+	PrimaryStyle   StaticRef   `json:"primaryStyle"`
+	SubStyle       StaticRef   `json:"subStyle"`
+	Keystone       StaticRef   `json:"keystone"`
+	PrimaryRunes   []StaticRef `json:"primaryRunes"`
+	SecondaryRunes []StaticRef `json:"secondaryRunes"`
+	StatShards     []StaticRef `json:"statShards"` // Offense, flex, defense
+	Games          int         `json:"games"`
+	Wins           int         `json:"wins"`
+	WinRate        float64     `json:"winRate"`
+}
+
+// ChampionRuneStats holds a player's rune pages on one champion, most used first
+type ChampionRuneStats struct {
+	ChampionName  string          `json:"championName"`
+	ChampionID    int             `json:"championId"`
+	GamesAnalyzed int             `json:"gamesAnalyzed"`
+	Pages         []RunePageStats `json:"pages"`
+}
+
+// PlayerRunesResponse is the response for a player's rune page analysis
+type PlayerRunesResponse struct {
+	PUUID     string                       `json:"puuid"`
+	Region    string                       `json:"region"`
+	RiotID    string                       `json:"riotId"`
+	Champions map[string]ChampionRuneStats `json:"champions"`
+}
+
+// SummonerSpellUsage is how often a player took a summoner spell and how often they cast it
+type SummonerSpellUsage struct {
+	Spell      StaticRef `json:"spell" bson:"spell"`
+	Games      int       `json:"games" bson:"games"`
+	GamesOnD   int       `json:"gamesOnD" bson:"gamesOnD"`
+	GamesOnF   int       `json:"gamesOnF" bson:"gamesOnF"`
+	TotalCasts int       `json:"totalCasts" bson:"totalCasts"`
+	AvgCasts   float64   `json:"avgCasts" bson:"avgCasts"` // Per game, over games with cast counts recorded
+}
+
+// SummonerSpellPairStats is one spell combination's results on a champion and role
+type SummonerSpellPairStats struct {
+	ChampionName string      `json:"championName" bson:"championName"`
+	ChampionID   int         `json:"championId" bson:"championId"`
+	Role         string      `json:"role" bson:"role"`
+	Spells       []StaticRef `json:"spells" bson:"spells"` // Ordered by spell ID, regardless of slot
+	Games        int         `json:"games" bson:"games"`
+	Wins         int         `json:"wins" bson:"wins"`
+	WinRate      float64     `json:"winRate" bson:"winRate"`
+}
+
+// SummonerSpellSummary holds a player's summoner spell habits
+type SummonerSpellSummary struct {
+	FlashKey string                   `json:"flashKey,omitempty" bson:"flashKey,omitempty"` // "D" or "F", empty if Flash was never taken
+	Spells   []SummonerSpellUsage     `json:"spells" bson:"spells"`
+	Pairs    []SummonerSpellPairStats `json:"pairs" bson:"pairs"`
+}
+
+// MetricPercentile is a player's value for one metric and the share of the population it beats:
+// below it for most metrics, above it for deaths/min
+type MetricPercentile struct {
+	Value      float64 `json:"value" bson:"value"`
+	Percentile float64 `json:"percentile" bson:"percentile"` // 0-100, higher is better
+}
+
+// PercentileStats annotates OverallStats or RoleStats with population percentiles
+type PercentileStats struct {
+	Tier              string            `json:"tier,omitempty" bson:"tier,omitempty"` // Empty when compared against all tiers
+	SampleSize        int               `json:"sampleSize" bson:"sampleSize"`
+	CSPerMin          *MetricPercentile `json:"csPerMin,omitempty" bson:"csPerMin,omitempty"`
+	VisionPerMin      *MetricPercentile `json:"visionPerMin,omitempty" bson:"visionPerMin,omitempty"`
+	DamagePerMin      *MetricPercentile `json:"damagePerMin,omitempty" bson:"damagePerMin,omitempty"`
+	GoldPerMin        *MetricPercentile `json:"goldPerMin,omitempty" bson:"goldPerMin,omitempty"`
+	KillParticipation *MetricPercentile `json:"killParticipation,omitempty" bson:"killParticipation,omitempty"`
+	DeathsPerMin      *MetricPercentile `json:"deathsPerMin,omitempty" bson:"deathsPerMin,omitempty"`
+	HealShieldPerMin  *MetricPercentile `json:"healShieldPerMin,omitempty" bson:"healShieldPerMin,omitempty"` // Healing and shielding on teammates
+}
+
+// BenchmarkBucket holds the population distribution of each metric for one role and rank tier.
+// Each distribution lists the metric's value at evenly spaced quantiles, lowest first.
+type BenchmarkBucket struct {
+	ID            string               `json:"id" bson:"_id"` // role|tier
+	Role          string               `json:"role" bson:"role"`
+	Tier          string               `json:"tier" bson:"tier"` // Empty for the all-tiers bucket
+	SampleSize    int                  `json:"sampleSize" bson:"sampleSize"`
+	Distributions map[string][]float64 `json:"distributions" bson:"distributions"`
+	UpdatedAt     int64                `json:"updatedAt" bson:"updatedAt"`
+}
+
+// StreakInfo is a run of consecutive wins or losses
+type StreakInfo struct {
+	Type   string `json:"type,omitempty"` // "win" or "loss"
+	Length int    `json:"length"`
+}
+
+// SessionPerformance aggregates results over a group of games
+type SessionPerformance struct {
+	GameNumber          int     `json:"gameNumber,omitempty"` // Position within a session, for per-game-number stats
+	Games               int     `json:"games"`
+	Wins                int     `json:"wins"`
+	WinRate             float64 `json:"winRate"`
+	AvgKDA              float64 `json:"avgKDA"`
+	AvgPerformanceScore float64 `json:"avgPerformanceScore"`
+}
+
+// PlaySession is a run of games without a long break between them
+type PlaySession struct {
+	StartTime         int64              `json:"startTime"` // Unix ms, creation of the first game
+	EndTime           int64              `json:"endTime"`   // Unix ms, end of the last game
+	Performance       SessionPerformance `json:"performance"`
+	LongestWinStreak  int                `json:"longestWinStreak"`
+	LongestLossStreak int                `json:"longestLossStreak"`
+	MatchIDs          []string           `json:"matchIds"` // Oldest first
+}
+
+// SessionAnalysis groups a player's matches into play sessions and compares how they play
+// as a session goes on and after wins versus losses
+type SessionAnalysis struct {
+	SessionGapMinutes int                  `json:"sessionGapMinutes"`
+	CurrentStreak     StreakInfo           `json:"currentStreak"`
+	LongestWinStreak  int                  `json:"longestWinStreak"`
+	LongestLossStreak int                  `json:"longestLossStreak"`
+	Sessions          []PlaySession        `json:"sessions"`     // Newest first
+	ByGameNumber      []SessionPerformance `json:"byGameNumber"` // First game of a session first
+	AfterWin          SessionPerformance   `json:"afterWin"`     // Games following a win in the same session
+	AfterLoss         SessionPerformance   `json:"afterLoss"`    // Games following a loss in the same session
+}
+
+// HeatmapCell holds results for one weekday and hour
+type HeatmapCell struct {
+	Games   int     `json:"games"`
+	Wins    int     `json:"wins"`
+	WinRate float64 `json:"winRate"`
+	AvgKDA  float64 `json:"avgKDA"`
+}
+
+// PlayerHeatmapResponse is the response for a player's time-of-day and day-of-week heatmap
+type PlayerHeatmapResponse struct {
+	PUUID        string             `json:"puuid"`
+	Region       string             `json:"region"`
+	RiotID       string             `json:"riotId"`
+	Timezone     string             `json:"timezone"`
+	TotalMatches int                `json:"totalMatches"`
+	Cells        [7][24]HeatmapCell `json:"cells"` // Indexed [weekday][hour] in Timezone, Sunday first
+}
+
+// ExportFilters narrows an export to a queue and a range of game start times
+type ExportFilters struct {
+	QueueID   int
+	StartTime int64 // Unix seconds, 0 for unbounded
+	EndTime   int64 // Unix seconds, 0 for unbounded
+}
+
+// MatchExportRow is one exported match, flattened and with static data names resolved
+type MatchExportRow struct {
+	MatchID            string   `json:"matchId" parquet:"matchId"`
+	GameCreation       int64    `json:"gameCreation" parquet:"gameCreation"` // Unix ms
+	GameDuration       int64    `json:"gameDuration" parquet:"gameDuration"` // Seconds
+	GameMode           string   `json:"gameMode" parquet:"gameMode"`
+	QueueID            int      `json:"queueId" parquet:"queueId"`
+	Patch              string   `json:"patch" parquet:"patch"`
+	ChampionID         int      `json:"championId" parquet:"championId"`
+	ChampionName       string   `json:"championName" parquet:"championName"`
+	Role               string   `json:"role" parquet:"role"`
+	Win                bool     `json:"win" parquet:"win"`
+	Kills              int      `json:"kills" parquet:"kills"`
+	Deaths             int      `json:"deaths" parquet:"deaths"`
+	Assists            int      `json:"assists" parquet:"assists"`
+	KDA                float64  `json:"kda" parquet:"kda"`
+	KillParticipation  float64  `json:"killParticipation" parquet:"killParticipation"`
+	CS                 int      `json:"cs" parquet:"cs"`
+	VisionScore        int      `json:"visionScore" parquet:"visionScore"`
+	GoldEarned         int      `json:"goldEarned" parquet:"goldEarned"`
+	DamageToChampions  int      `json:"damageToChampions" parquet:"damageToChampions"`
+	DamageToTurrets    int      `json:"damageToTurrets" parquet:"damageToTurrets"`
+	DamageToObjectives int      `json:"damageToObjectives" parquet:"damageToObjectives"`
+	TotalDamageTaken   int      `json:"totalDamageTaken" parquet:"totalDamageTaken"`
+	ChampLevel         int      `json:"champLevel" parquet:"champLevel"`
+	TeamID             int      `json:"teamId" parquet:"teamId"`
+	Items              []string `json:"items" parquet:"items,list"`
+	SummonerSpells     []string `json:"summonerSpells" parquet:"summonerSpells,list"`
+	Keystone           string   `json:"keystone" parquet:"keystone"`
+	PrimaryStyle       string   `json:"primaryStyle" parquet:"primaryStyle"`
+	SecondaryStyle     string   `json:"secondaryStyle" parquet:"secondaryStyle"`
+	Runes              []string `json:"runes" parquet:"runes,list"`
+	PerformanceScore   float64  `json:"performanceScore" parquet:"performanceScore"`
+	PerformanceGrade   string   `json:"performanceGrade" parquet:"performanceGrade"`
+	PerformanceBadge   string   `json:"performanceBadge" parquet:"performanceBadge"`
+}
+
+// ErrorDetail describes one problem with a request, usually a single parameter
+type ErrorDetail struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ErrorBody is the payload of every API error response
+type ErrorBody struct {
+	Code       string        `json:"code"`
+	Status     int           `json:"status"`
+	Message    string        `json:"message"`
+	RetryAfter int           `json:"retryAfter,omitempty"` // Seconds; also sent as the Retry-After header
+	RequestID  string        `json:"requestId,omitempty"`
+	Details    []ErrorDetail `json:"details,omitempty"`
+}
+
+// ErrorResponse wraps ErrorBody as {"error": {...}}
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
 }
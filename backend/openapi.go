@@ -0,0 +1,173 @@
+package main
+
+import (
+	_ "embed"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// openapi.json describes every route by hand, but its components.schemas section is generated
+// from the response types below. Regenerate it after changing those types with:
+//
+//	go test -run TestOpenAPIContract -update-openapi
+//
+//go:embed openapi.json
+var openAPIDocument []byte
+
+//go:embed docs.html
+var apiDocsPage []byte
+
+// openAPISchemaRoots are the request and response types referenced from openapi.json paths.
+// Types they reference are picked up automatically.
+var openAPISchemaRoots = []reflect.Type{
+	reflect.TypeOf(PaginatedDashboardResponse{}),
+	reflect.TypeOf(DashboardResponseV2{}),
+	reflect.TypeOf(LegacyRouteUsage{}),
+	reflect.TypeOf(UserPerformance{}),
+	reflect.TypeOf(RecentGamesSummary{}),
+	reflect.TypeOf(StaticDataResponse{}),
+	reflect.TypeOf(MatchDto{}),
+	reflect.TypeOf(MatchScoreboard{}),
+	reflect.TypeOf(PlayerBuildsResponse{}),
+	reflect.TypeOf(PlayerRunesResponse{}),
+	reflect.TypeOf(PlayerHeatmapResponse{}),
+	reflect.TypeOf(MatchExportRow{}),
+	reflect.TypeOf(ItemStatsResponse{}),
+	reflect.TypeOf(ChampionTierResponse{}),
+	reflect.TypeOf(TrackPlayerRequest{}),
+	reflect.TypeOf(BatchPlayersRequest{}),
+	reflect.TypeOf(BatchPlayersResponse{}),
+	reflect.TypeOf(PlayerAccount{}),
+	reflect.TypeOf(SearchResponse{}),
+	reflect.TypeOf(TrackedPlayer{}),
+	reflect.TypeOf(FetchJobRequest{}),
+	reflect.TypeOf(FetchJob{}),
+	reflect.TypeOf(DashboardStreamMeta{}),
+	reflect.TypeOf(IncrementalStats{}),
+	reflect.TypeOf(ErrorResponse{}),
+}
+
+// openAPISchemas builds a JSON Schema (OpenAPI 3.1 flavour) for every named struct reachable
+// from roots, following the same field rules as encoding/json
+func openAPISchemas(roots []reflect.Type) map[string]interface{} {
+	schemas := map[string]interface{}{}
+	for _, root := range roots {
+		openAPISchemaFor(root, schemas)
+	}
+	return schemas
+}
+
+func openAPIRef(name string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+// openAPISchemaFor returns the schema for t, registering named structs in schemas and
+// referring to them by $ref
+func openAPISchemaFor(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return openAPISchemaFor(t.Elem(), schemas)
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": openAPISchemaFor(t.Elem(), schemas)}
+	case reflect.Array:
+		return map[string]interface{}{
+			"type":     "array",
+			"items":    openAPISchemaFor(t.Elem(), schemas),
+			"minItems": t.Len(),
+			"maxItems": t.Len(),
+		}
+	case reflect.Map:
+		// encoding/json writes integer map keys as strings, so every map is an object
+		return map[string]interface{}{"type": "object", "additionalProperties": openAPISchemaFor(t.Elem(), schemas)}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return openAPIStructSchema(t, schemas)
+		}
+		if _, ok := schemas[t.Name()]; !ok {
+			schemas[t.Name()] = nil // Placeholder so recursive types terminate
+			schemas[t.Name()] = openAPIStructSchema(t, schemas)
+		}
+		return openAPIRef(t.Name())
+	default:
+		// interface{} and anything else encoding/json can't describe statically
+		return map[string]interface{}{}
+	}
+}
+
+func openAPIStructSchema(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []interface{}{}
+	openAPICollectFields(t, schemas, properties, &required)
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// openAPICollectFields adds t's JSON fields to properties, flattening embedded structs.
+// Fields without omitempty are always present in responses, so they are required; pointers
+// without omitempty may be null.
+func openAPICollectFields(t reflect.Type, schemas map[string]interface{}, properties map[string]interface{}, required *[]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		omitEmpty := strings.Contains(opts, "omitempty")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				openAPICollectFields(embedded, schemas, properties, required)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema := openAPISchemaFor(field.Type, schemas)
+		if field.Type.Kind() == reflect.Ptr && !omitEmpty {
+			schema = map[string]interface{}{"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}}}
+		}
+		properties[name] = schema
+		if !omitEmpty {
+			*required = append(*required, name)
+		}
+	}
+}
+
+func getOpenAPIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPIDocument)
+}
+
+// getAPIDocsHandler serves a self-contained docs page that renders /api/openapi.json
+func getAPIDocsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(apiDocsPage)
+}
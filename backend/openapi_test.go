@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var updateOpenAPI = flag.Bool("update-openapi", false, "rewrite components.schemas in openapi.json from the Go types")
+
+// normalizeJSON round-trips v through encoding/json so generated and parsed values compare equal
+func normalizeJSON(t *testing.T, v interface{}) interface{} {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return out
+}
+
+// collectRefs appends every $ref found in v
+func collectRefs(v interface{}, refs *[]string) {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		for key, value := range node {
+			if ref, ok := value.(string); ok && key == "$ref" {
+				*refs = append(*refs, ref)
+				continue
+			}
+			collectRefs(value, refs)
+		}
+	case []interface{}:
+		for _, value := range node {
+			collectRefs(value, refs)
+		}
+	}
+}
+
+// TestOpenAPIContract fails when the response types drift from the schemas in openapi.json
+func TestOpenAPIContract(t *testing.T) {
+	var document map[string]interface{}
+	if err := json.Unmarshal(openAPIDocument, &document); err != nil {
+		t.Fatalf("openapi.json is not valid JSON: %v", err)
+	}
+	if version, _ := document["openapi"].(string); !strings.HasPrefix(version, "3.") {
+		t.Fatalf("openapi.json declares version %q, want 3.x", version)
+	}
+
+	generated := normalizeJSON(t, openAPISchemas(openAPISchemaRoots)).(map[string]interface{})
+
+	if *updateOpenAPI {
+		document["components"] = map[string]interface{}{"schemas": generated}
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(document); err != nil {
+			t.Fatalf("encode openapi.json: %v", err)
+		}
+		if err := os.WriteFile("openapi.json", buf.Bytes(), 0o644); err != nil {
+			t.Fatalf("write openapi.json: %v", err)
+		}
+		t.Log("openapi.json updated; rebuild to embed it")
+		return
+	}
+
+	components, _ := document["components"].(map[string]interface{})
+	documented, _ := components["schemas"].(map[string]interface{})
+
+	var names []string
+	for name := range generated {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		spec, ok := documented[name]
+		if !ok {
+			t.Errorf("schema %s is missing from openapi.json", name)
+			continue
+		}
+		if !reflect.DeepEqual(spec, generated[name]) {
+			want, _ := json.MarshalIndent(generated[name], "", "  ")
+			t.Errorf("schema %s in openapi.json does not match the Go type; it should be:\n%s", name, want)
+		}
+	}
+	for name := range documented {
+		if _, ok := generated[name]; !ok {
+			t.Errorf("openapi.json documents schema %s, which no response type uses", name)
+		}
+	}
+	if t.Failed() {
+		t.Log("Run `go test -run TestOpenAPIContract -update-openapi` to regenerate the schemas")
+	}
+
+	// Every path must only refer to schemas that exist
+	var refs []string
+	collectRefs(document["paths"], &refs)
+	for _, ref := range refs {
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		if _, ok := documented[name]; !ok {
+			t.Errorf("openapi.json path refers to unknown schema %s", ref)
+		}
+	}
+}
+
+// TestOpenAPIDashboardFields spot-checks the main dashboard payload against what the handler encodes
+func TestOpenAPIDashboardFields(t *testing.T) {
+	var document struct {
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]interface{} `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(openAPIDocument, &document); err != nil {
+		t.Fatalf("openapi.json is not valid JSON: %v", err)
+	}
+
+	encoded, err := json.Marshal(PaginatedDashboardResponse{Summary: &RecentGamesSummary{}})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	properties := document.Components.Schemas["PaginatedDashboardResponse"].Properties
+	for field := range fields {
+		if _, ok := properties[field]; !ok {
+			t.Errorf("PaginatedDashboardResponse encodes %q, which openapi.json does not document", field)
+		}
+	}
+}
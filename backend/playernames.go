@@ -0,0 +1,465 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-redis/redis/v8"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	nameHistoryCollection      = "namehistory"
+	accountCacheDuration       = 1 * time.Hour
+	renamedRiotIDKeyPrefix     = "renamed:"
+	renamedRiotIDCacheDuration = 1 * time.Hour
+	renamedRiotIDCandidates    = 5 // Name history documents considered for one old Riot ID
+	mongoDuplicateKeyCode      = 11000
+)
+
+// nameSighting is a PUUID seen under a Riot ID at SeenAt (Unix seconds)
+type nameSighting struct {
+	PUUID    string
+	GameName string
+	TagLine  string
+	SeenAt   int64
+}
+
+// riotIDKey is the case-insensitive form of a Riot ID used to match name history entries
+func riotIDKey(gameName, tagLine string) string {
+	return strings.ToLower(gameName) + "#" + strings.ToLower(tagLine)
+}
+
+// puuidCacheKey is the Redis key getPUUID caches a Riot ID's PUUID under
+func puuidCacheKey(apiRegion, gameName, tagLine string) string {
+	return fmt.Sprintf("puuid:%s:%s:%s", apiRegion, strings.ToLower(gameName), strings.ToLower(tagLine))
+}
+
+// nameSightingsFromMatch returns the Riot ID every participant played the match under
+func nameSightingsFromMatch(match *MatchDto) []nameSighting {
+	if match == nil {
+		return nil
+	}
+	seenAt := match.Info.GameCreation / 1000
+	sightings := make([]nameSighting, 0, len(match.Info.Participants))
+	for _, p := range match.Info.Participants {
+		// Matches from before Riot IDs only carry summoner names
+		if p.PUUID == "" || p.RiotIDGameName == "" || p.RiotIDTagline == "" {
+			continue
+		}
+		sightings = append(sightings, nameSighting{PUUID: p.PUUID, GameName: p.RiotIDGameName, TagLine: p.RiotIDTagline, SeenAt: seenAt})
+	}
+	return sightings
+}
+
+// recordNameSightings adds each sighting to its PUUID's name history. The most recent sighting
+// decides the current Riot ID, so ingesting an old match never undoes a rename.
+func recordNameSightings(app *GlobalAppData, sightings []nameSighting) {
+	if len(sightings) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	known := make([]mongo.WriteModel, 0, 2*len(sightings))
+	added := make([]mongo.WriteModel, 0, len(sightings))
+	for _, s := range sightings {
+		if err := PreventNoSQLInjection(s.PUUID); err != nil {
+			log.Printf("Name history: Skipping suspicious PUUID %s", s.PUUID)
+			continue
+		}
+		key := riotIDKey(s.GameName, s.TagLine)
+
+		// Widen the seen window of a Riot ID already in the history
+		known = append(known, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": s.PUUID, "names.key": key}).
+			SetUpdate(bson.M{
+				"$min": bson.M{"names.$.firstSeen": s.SeenAt},
+				"$max": bson.M{"names.$.lastSeen": s.SeenAt},
+			}))
+		known = append(known, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": s.PUUID, "seenAt": bson.M{"$lte": s.SeenAt}}).
+			SetUpdate(bson.M{"$set": bson.M{"gameName": s.GameName, "tagLine": s.TagLine, "currentKey": key, "seenAt": s.SeenAt}}))
+
+		// Append a Riot ID not in the history yet, creating the document for a new PUUID. When the
+		// Riot ID is already listed the filter misses and the upsert fails with a duplicate key.
+		added = append(added, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": s.PUUID, "names.key": bson.M{"$ne": key}}).
+			SetUpdate(bson.M{
+				"$push":        bson.M{"names": RiotIDRecord{Key: key, GameName: s.GameName, TagLine: s.TagLine, FirstSeen: s.SeenAt, LastSeen: s.SeenAt}},
+				"$setOnInsert": bson.M{"gameName": s.GameName, "tagLine": s.TagLine, "currentKey": key, "seenAt": s.SeenAt},
+			}).
+			SetUpsert(true))
+	}
+	if len(added) == 0 {
+		return
+	}
+
+	collection := app.mongoClient.Database(app.mongoDatabase).Collection(nameHistoryCollection)
+	opts := options.BulkWrite().SetOrdered(false)
+	if _, err := collection.BulkWrite(ctx, known, opts); err != nil {
+		log.Printf("Name history: Error updating %d known Riot IDs: %v", len(sightings), err)
+	}
+	if _, err := collection.BulkWrite(ctx, added, opts); err != nil && !onlyDuplicateKeyErrors(err) {
+		log.Printf("Name history: Error adding %d Riot IDs: %v", len(sightings), err)
+	}
+}
+
+// onlyDuplicateKeyErrors reports whether every failed write of a bulk write hit a duplicate key
+func onlyDuplicateKeyErrors(err error) bool {
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil {
+		return false
+	}
+	for _, writeErr := range bulkErr.WriteErrors {
+		if writeErr.Code != mongoDuplicateKeyCode {
+			return false
+		}
+	}
+	return true
+}
+
+// loadNameHistory returns the name history of puuid, or nil when it has never been seen
+func loadNameHistory(ctx context.Context, app *GlobalAppData, puuid string) (*NameHistory, error) {
+	collection := app.mongoClient.Database(app.mongoDatabase).Collection(nameHistoryCollection)
+	var history NameHistory
+	err := collection.FindOne(ctx, bson.M{"_id": puuid}).Decode(&history)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load name history: %w", err)
+	}
+	return &history, nil
+}
+
+// getAccountByPUUID looks up the Riot ID a PUUID currently goes by through account-v1
+func getAccountByPUUID(app *GlobalAppData, region, puuid string) (*AccountDTO, error) {
+	apiRegion := getAPIRegion(region)
+	cacheKey := fmt.Sprintf("account:%s:%s", apiRegion, puuid)
+
+	val, err := app.redisClient.Get(context.Background(), cacheKey).Result()
+	if err == nil {
+		var acc AccountDTO
+		if err := json.Unmarshal([]byte(val), &acc); err == nil {
+			return &acc, nil
+		}
+	} else if err != redis.Nil {
+		return nil, fmt.Errorf("failed to get account from cache: %w", err)
+	}
+
+	requestURL := fmt.Sprintf("https://%s.api.riotgames.com/riot/account/v1/accounts/by-puuid/%s", apiRegion, url.PathEscape(puuid))
+	req, _ := http.NewRequest("GET", requestURL, nil)
+	req.Header.Set("X-Riot-Token", app.riotAPIKey)
+
+	// Get HTTP client from pool
+	client := riotClientPool.Get().(*http.Client)
+	defer riotClientPool.Put(client)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make account request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, newRiotAPIError(riotEndpointAccount, resp, bodyBytes)
+	}
+
+	var acc AccountDTO
+	if err := json.NewDecoder(resp.Body).Decode(&acc); err != nil {
+		return nil, fmt.Errorf("failed to decode account response: %w", err)
+	}
+	if acc.PUUID == "" || acc.GameName == "" {
+		return nil, fmt.Errorf("no Riot ID found for PUUID %s in region %s", puuid, region)
+	}
+
+	// Move Redis caching off the critical path - run asynchronously. The current Riot ID also
+	// warms the PUUID cache for lookups by name.
+	go func(acc AccountDTO) {
+		cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if accJSON, err := json.Marshal(acc); err == nil {
+			_ = app.redisClient.Set(cacheCtx, cacheKey, accJSON, accountCacheDuration).Err()
+		}
+		_ = app.redisClient.Set(cacheCtx, puuidCacheKey(apiRegion, acc.GameName, acc.TagLine), acc.PUUID, puuidCacheDuration).Err()
+	}(acc)
+	go recordNameSightings(app, []nameSighting{{PUUID: acc.PUUID, GameName: acc.GameName, TagLine: acc.TagLine, SeenAt: time.Now().Unix()}})
+
+	return &acc, nil
+}
+
+// refreshRiotID replaces a stored Riot ID that predates a rename with the one the player was
+// just looked up by
+func refreshRiotID(app *GlobalAppData, perf *UserPerformance, gameName, tagLine string) {
+	riotID := gameName + "#" + tagLine
+	if strings.EqualFold(perf.RiotID, riotID) {
+		return
+	}
+	perf.RiotID = riotID
+
+	go func(puuid, region, riotID string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		collection := app.mongoClient.Database(app.mongoDatabase).Collection("userperformances")
+		if _, err := collection.UpdateOne(ctx, bson.M{"_id": puuid, "region": region}, bson.M{"$set": bson.M{"riotId": riotID}}); err != nil {
+			log.Printf("Warning: Failed to update Riot ID of %s: %v", puuid, err)
+		}
+	}(perf.PUUID, perf.Region, riotID)
+}
+
+// renamedFrom returns the PUUID that most recently went by the Riot ID key and has since
+// renamed, or "" when the Riot ID is unknown or someone holds it now
+func renamedFrom(ctx context.Context, app *GlobalAppData, key string) (string, error) {
+	collection := app.mongoClient.Database(app.mongoDatabase).Collection(nameHistoryCollection)
+	cursor, err := collection.Find(ctx, bson.M{"names.key": key}, options.Find().SetLimit(renamedRiotIDCandidates))
+	if err != nil {
+		return "", fmt.Errorf("failed to query name history: %w", err)
+	}
+	var histories []NameHistory
+	if err := cursor.All(ctx, &histories); err != nil {
+		return "", fmt.Errorf("failed to decode name history: %w", err)
+	}
+
+	puuid, lastSeen := "", int64(-1)
+	for _, history := range histories {
+		if history.CurrentKey == key {
+			return "", nil
+		}
+		for _, name := range history.Names {
+			if name.Key == key && name.LastSeen > lastSeen {
+				puuid, lastSeen = history.PUUID, name.LastSeen
+			}
+		}
+	}
+	return puuid, nil
+}
+
+// resolveRenamedRiotID returns the current account of the player who renamed away from
+// gameName#tagLine, or nil when the Riot ID should be served as is. A rename found in the
+// name history is only trusted once account-v1 confirms it and nobody has claimed the old Riot
+// ID since.
+func resolveRenamedRiotID(ctx context.Context, app *GlobalAppData, region, gameName, tagLine string) (*AccountDTO, error) {
+	apiRegion := getAPIRegion(region)
+	key := riotIDKey(gameName, tagLine)
+	redirectKey := renamedRiotIDKeyPrefix + apiRegion + ":" + key
+
+	puuid, err := app.redisClient.Get(ctx, redirectKey).Result()
+	confirmed := err == nil
+	if err == redis.Nil {
+		if puuid, err = renamedFrom(ctx, app, key); err != nil || puuid == "" {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get renamed Riot ID from cache: %w", err)
+	}
+
+	account, err := getAccountByPUUID(app, region, puuid)
+	if err != nil {
+		return nil, err
+	}
+	if riotIDKey(account.GameName, account.TagLine) == key {
+		return nil, nil
+	}
+	if confirmed {
+		return account, nil
+	}
+
+	// The cached PUUID of the old Riot ID is stale, so ask Riot who holds it now
+	_ = app.redisClient.Del(ctx, puuidCacheKey(apiRegion, gameName, tagLine)).Err()
+	holder, err := getPUUID(app, region, gameName, tagLine)
+	var riotErr *RiotAPIError
+	switch {
+	case err == nil:
+		log.Printf("Riot ID %s#%s was renamed by %s and now belongs to %s", gameName, tagLine, puuid, holder)
+		return nil, nil
+	case errors.As(err, &riotErr) && riotErr.StatusCode == http.StatusNotFound:
+	default:
+		return nil, err
+	}
+
+	// Move Redis caching off the critical path - run asynchronously
+	go func(key, value string) {
+		cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = app.redisClient.Set(cacheCtx, key, value, renamedRiotIDCacheDuration).Err()
+	}(redirectKey, puuid)
+
+	return account, nil
+}
+
+// playerRoutePath rewrites the request path, whose player is identified by the three segments
+// after .../player/, to the name-based route for gameName#tagLine
+func playerRoutePath(r *http.Request, region, gameName, tagLine string) string {
+	segments := strings.Split(r.URL.EscapedPath(), "/")
+	for i, segment := range segments {
+		if segment != "player" || i+3 >= len(segments) {
+			continue
+		}
+		rest := segments[i+4:]
+		segments = append(segments[:i+1:i+1], url.PathEscape(region), url.PathEscape(gameName), url.PathEscape(tagLine))
+		segments = append(segments, rest...)
+		break
+	}
+	path := strings.Join(segments, "/")
+	if r.URL.RawQuery != "" {
+		path += "?" + r.URL.RawQuery
+	}
+	return path
+}
+
+// renamedRiotIDMiddleware redirects player routes addressed by an old Riot ID to the Riot ID
+// the player goes by now. Anything it cannot decide is left to the handler.
+func renamedRiotIDMiddleware(app *GlobalAppData) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if app.riotAPIKey == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			gameName, tagLine, region, err := ValidateAndSanitizeInput(chi.URLParam(r, "gameName"), chi.URLParam(r, "tagLine"), chi.URLParam(r, "region"))
+			if err != nil || PreventNoSQLInjection(gameName) != nil || PreventNoSQLInjection(tagLine) != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			account, err := resolveRenamedRiotID(r.Context(), app, region, gameName, tagLine)
+			if err != nil {
+				log.Printf("Warning: Could not check %s#%s for a rename: %v", gameName, tagLine, err)
+			}
+			if account == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			log.Printf("Redirecting renamed Riot ID %s#%s to %s#%s", gameName, tagLine, account.GameName, account.TagLine)
+			w.Header().Set("Cache-Control", cacheControlPlayer)
+			http.Redirect(w, r, playerRoutePath(r, region, account.GameName, account.TagLine), http.StatusTemporaryRedirect)
+		})
+	}
+}
+
+// validatePUUIDRequest reads the region and PUUID of a by-puuid route, writing the error
+// response itself when they are invalid
+func validatePUUIDRequest(w http.ResponseWriter, r *http.Request, app *GlobalAppData) (string, string, bool) {
+	region := SanitizeString(chi.URLParam(r, "region"))
+	puuid := chi.URLParam(r, "puuid")
+
+	if err := ValidateRegion(region); err != nil {
+		writeValidationError(w, r, err, "Invalid region")
+		return "", "", false
+	}
+	if err := ValidatePUUID(puuid); err != nil {
+		writeValidationError(w, r, err, "Invalid puuid")
+		return "", "", false
+	}
+	if err := PreventNoSQLInjection(puuid); err != nil {
+		log.Printf("Potential NoSQL injection attempt in puuid: %s", puuid)
+		writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
+		return "", "", false
+	}
+
+	if app.riotAPIKey == "" {
+		log.Println("Error: RIOT_API_KEY is not set.")
+		writeAPIError(w, r, newAPIError(http.StatusInternalServerError, ErrCodeServerMisconfigured, "Server configuration error: Riot API Key not set."))
+		return "", "", false
+	}
+	return strings.ToLower(region), puuid, true
+}
+
+// getPlayerByPUUIDHandler returns the Riot ID a PUUID goes by now and every Riot ID it has
+// been seen under
+func getPlayerByPUUIDHandler(app *GlobalAppData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		region, puuid, ok := validatePUUIDRequest(w, r, app)
+		if !ok {
+			return
+		}
+
+		account, err := getAccountByPUUID(app, region, puuid)
+		if err != nil {
+			log.Printf("Error looking up account for PUUID %s: %v", puuid, err)
+			writeError(w, r, err, "Error looking up player")
+			return
+		}
+		history, err := loadNameHistory(r.Context(), app, puuid)
+		if err != nil {
+			log.Printf("Error loading name history for %s: %v", puuid, err)
+			writeError(w, r, err, "Error loading name history")
+			return
+		}
+
+		response := PlayerAccount{
+			PUUID:       account.PUUID,
+			Region:      region,
+			GameName:    account.GameName,
+			TagLine:     account.TagLine,
+			RiotID:      account.GameName + "#" + account.TagLine,
+			NameHistory: []RiotIDRecord{},
+		}
+		currentKey := riotIDKey(account.GameName, account.TagLine)
+		listed := false
+		if history != nil {
+			for _, name := range history.Names {
+				listed = listed || name.Key == currentKey
+				response.NameHistory = append(response.NameHistory, name)
+			}
+		}
+		// The lookup above records the current Riot ID in the background, so it may not be stored yet
+		if !listed {
+			now := time.Now().Unix()
+			response.NameHistory = append(response.NameHistory, RiotIDRecord{Key: currentKey, GameName: account.GameName, TagLine: account.TagLine, FirstSeen: now, LastSeen: now})
+		}
+		sort.SliceStable(response.NameHistory, func(i, j int) bool {
+			return response.NameHistory[i].LastSeen > response.NameHistory[j].LastSeen
+		})
+
+		etagParts := []string{response.PUUID, response.RiotID}
+		for _, name := range response.NameHistory {
+			etagParts = append(etagParts, fmt.Sprintf("%s:%d:%d", name.Key, name.FirstSeen, name.LastSeen))
+		}
+		if notModified(w, r, strongETag(etagParts...), cacheControlPlayer) {
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding player account response for %s: %v", puuid, err)
+		}
+	}
+}
+
+// redirectPUUIDRouteHandler serves every per-player route by PUUID by redirecting to the same
+// route for the player's current Riot ID
+func redirectPUUIDRouteHandler(app *GlobalAppData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		region, puuid, ok := validatePUUIDRequest(w, r, app)
+		if !ok {
+			return
+		}
+
+		account, err := getAccountByPUUID(app, region, puuid)
+		if err != nil {
+			log.Printf("Error looking up account for PUUID %s: %v", puuid, err)
+			writeError(w, r, err, "Error looking up player")
+			return
+		}
+
+		w.Header().Set("Cache-Control", cacheControlPlayer)
+		http.Redirect(w, r, playerRoutePath(r, region, account.GameName, account.TagLine), http.StatusTemporaryRedirect)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// queueNames gives the common queues the names players know them by; the rest fall back to
+// the description from queues.json
+var queueNames = map[int]string{
+	0:    "Custom",
+	400:  "Normal Draft",
+	420:  "Ranked Solo/Duo",
+	430:  "Normal Blind",
+	440:  "Ranked Flex",
+	450:  "ARAM",
+	490:  "Quickplay",
+	700:  "Clash",
+	720:  "ARAM Clash",
+	830:  "Co-op vs. AI Intro",
+	840:  "Co-op vs. AI Beginner",
+	850:  "Co-op vs. AI Intermediate",
+	900:  "ARURF",
+	1020: "One for All",
+	1700: "Arena",
+	1900: "URF",
+}
+
+// buildQueueCatalog indexes queues.json by queue ID and derives display names and the ranked flag
+func buildQueueCatalog(queues []QueueDto) map[int]QueueInfo {
+	catalog := make(map[int]QueueInfo, len(queues))
+	for _, q := range queues {
+		description := strings.TrimSpace(q.Description)
+		info := QueueInfo{
+			QueueID:    q.QueueID,
+			Map:        q.Map,
+			Ranked:     strings.Contains(description, "Ranked"),
+			Deprecated: strings.Contains(strings.ToLower(q.Notes), "deprecated"),
+		}
+		switch name, ok := queueNames[q.QueueID]; {
+		case ok:
+			info.Name = name
+		case description != "":
+			info.Name = strings.TrimSuffix(description, " games")
+		default:
+			info.Name = q.Map
+		}
+		catalog[q.QueueID] = info
+	}
+	return catalog
+}
+
+// resolveQueue looks up a queue in the catalog, falling back to a bare entry for queues it doesn't know
+func resolveQueue(sd *StaticData, queueID int) QueueInfo {
+	if sd != nil {
+		if info, ok := sd.Queues[queueID]; ok {
+			return info
+		}
+	}
+	info := QueueInfo{QueueID: queueID, Name: queueNames[queueID]}
+	if info.Name == "" {
+		info.Name = "Queue " + strconv.Itoa(queueID)
+	}
+	return info
+}
+
+// calculateQueueStats groups matches by queue, so Ranked Solo, Flex, Normal Draft and ARAM
+// results can be compared side by side instead of being blended into one line
+func calculateQueueStats(sd *StaticData, matches []PlayerMatchStats) map[string]QueueStats {
+	queueMap := make(map[int][]PlayerMatchStats)
+	for _, match := range matches {
+		queueMap[match.QueueID] = append(queueMap[match.QueueID], match)
+	}
+
+	queueStats := make(map[string]QueueStats, len(queueMap))
+	for queueID, queueMatches := range queueMap {
+		queueStats[strconv.Itoa(queueID)] = QueueStats{
+			Queue:       resolveQueue(sd, queueID),
+			GamesPlayed: len(queueMatches),
+			Stats:       calculateOverallStats(queueMatches),
+		}
+	}
+	return queueStats
+}
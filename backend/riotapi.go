@@ -26,6 +26,8 @@ const (
 	puuidCacheDuration           = 24 * time.Hour
 	matchListCacheDuration       = 1 * time.Hour
 	matchDetailsCacheDuration    = 7 * 24 * time.Hour
+	matchTimelineCacheDuration   = 7 * 24 * time.Hour
+	rankTierCacheDuration        = 6 * time.Hour
 	userPerformanceCacheDuration = 30 * time.Minute
 	staticDataCacheDuration      = 24 * time.Hour
 	defaultTimeout               = 10 * time.Second
@@ -33,6 +35,8 @@ const (
 	defaultQueueID               = 0
 	defaultConcurrencyLimit      = 25 // Tunable concurrency limit for match fetching
 	dataDragonBaseURL            = "https://ddragon.leagueoflegends.com"
+	riotStaticDocsBaseURL        = "https://static.developer.riotgames.com/docs/lol"
+	communityDragonBaseURL       = "https://raw.communitydragon.org/latest"
 )
 
 var (
@@ -55,7 +59,7 @@ var (
 
 func getPUUID(app *GlobalAppData, region, gameName, tagLine string) (string, error) {
 	apiRegion := getAPIRegion(region)
-	cacheKey := fmt.Sprintf("puuid:%s:%s:%s", apiRegion, strings.ToLower(gameName), strings.ToLower(tagLine))
+	cacheKey := puuidCacheKey(apiRegion, gameName, tagLine)
 
 	val, err := app.redisClient.Get(context.Background(), cacheKey).Result()
 	if err == redis.Nil {
@@ -75,7 +79,7 @@ func getPUUID(app *GlobalAppData, region, gameName, tagLine string) (string, err
 
 		if resp.StatusCode != http.StatusOK {
 			bodyBytes, _ := io.ReadAll(resp.Body)
-			return "", fmt.Errorf("PUUID request failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+			return "", newRiotAPIError(riotEndpointAccount, resp, bodyBytes)
 		}
 
 		var acc AccountDTO
@@ -93,6 +97,7 @@ func getPUUID(app *GlobalAppData, region, gameName, tagLine string) (string, err
 			defer cancel()
 			_ = app.redisClient.Set(cacheCtx, key, value, puuidCacheDuration).Err()
 		}(cacheKey, acc.PUUID)
+		go recordNameSightings(app, []nameSighting{{PUUID: acc.PUUID, GameName: acc.GameName, TagLine: acc.TagLine, SeenAt: time.Now().Unix()}})
 
 		return acc.PUUID, nil
 	} else if err != nil {
@@ -130,7 +135,7 @@ func getMatchIDs(app *GlobalAppData, region, puuid string, count int, queueID in
 
 		if resp.StatusCode != http.StatusOK {
 			bodyBytes, _ := io.ReadAll(resp.Body)
-			return nil, fmt.Errorf("match IDs request failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+			return nil, newRiotAPIError(riotEndpointMatchIDs, resp, bodyBytes)
 		}
 
 		var matchIDs []string
@@ -185,7 +190,7 @@ func getMatchDetails(app *GlobalAppData, region, matchID string) (*MatchDto, err
 				log.Printf("Match %s not found in region %s, skipping.", matchID, apiRegion)
 				return nil, nil
 			}
-			return nil, fmt.Errorf("match details request for %s failed with status %d: %s", matchID, resp.StatusCode, string(bodyBytes))
+			return nil, fmt.Errorf("match details request for %s: %w", matchID, newRiotAPIError(riotEndpointMatch, resp, bodyBytes))
 		}
 
 		bodyBytes, err := io.ReadAll(resp.Body)
@@ -205,6 +210,9 @@ func getMatchDetails(app *GlobalAppData, region, matchID string) (*MatchDto, err
 			_ = app.redisClient.Set(cacheCtx, key, string(data), matchDetailsCacheDuration).Err()
 		}(cacheKey, bodyBytes)
 
+		// Keep every participant for population-wide champion statistics
+		go ingestMatch(app, region, &match)
+
 		return &match, nil
 	} else if err != nil {
 		return nil, fmt.Errorf("failed to get match details for %s from cache: %w", matchID, err)
@@ -214,10 +222,149 @@ func getMatchDetails(app *GlobalAppData, region, matchID string) (*MatchDto, err
 	if err := json.Unmarshal([]byte(val), &match); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal cached match details for %s: %w", matchID, err)
 	}
+
+	// The match may have been cached before ingestion existed or by another instance
+	go ingestMatchOnce(app, region, &match)
+
 	return &match, nil
 }
 
+func getMatchTimeline(app *GlobalAppData, region, matchID string) (*MatchTimelineDto, error) {
+	apiRegion := getAPIRegion(region)
+	cacheKey := fmt.Sprintf("matchtimeline:%s:%s", apiRegion, matchID)
+
+	val, err := app.redisClient.Get(context.Background(), cacheKey).Result()
+	if err == redis.Nil {
+		url := fmt.Sprintf("https://%s.api.riotgames.com/lol/match/v5/matches/%s/timeline", apiRegion, matchID)
+		req, _ := http.NewRequest("GET", url, nil)
+		req.Header.Set("X-Riot-Token", app.riotAPIKey)
+
+		// Get HTTP client from pool
+		client := riotClientPool.Get().(*http.Client)
+		defer riotClientPool.Put(client)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to make match timeline request for %s: %w", matchID, err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			bodyBytes, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode == http.StatusNotFound {
+				log.Printf("Timeline for match %s not found in region %s, skipping.", matchID, apiRegion)
+				return nil, nil
+			}
+			return nil, fmt.Errorf("match timeline request for %s: %w", matchID, newRiotAPIError(riotEndpointTimeline, resp, bodyBytes))
+		}
+
+		var timeline MatchTimelineDto
+		if err := json.NewDecoder(resp.Body).Decode(&timeline); err != nil {
+			return nil, fmt.Errorf("failed to decode match timeline response for %s: %w", matchID, err)
+		}
+
+		// Raw timelines are several hundred KB, so only the decoded subset is cached
+		go func(key string, data MatchTimelineDto) {
+			cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if dataJSON, err := json.Marshal(data); err == nil {
+				_ = app.redisClient.Set(cacheCtx, key, dataJSON, matchTimelineCacheDuration).Err()
+			}
+		}(cacheKey, timeline)
+
+		return &timeline, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get match timeline for %s from cache: %w", matchID, err)
+	}
+
+	var timeline MatchTimelineDto
+	if err := json.Unmarshal([]byte(val), &timeline); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cached match timeline for %s: %w", matchID, err)
+	}
+	return &timeline, nil
+}
+
+// getRankTier returns the player's solo queue tier, falling back to flex, or "" when unranked.
+// League-v4 is routed by platform region, unlike Account-v1 and Match-v5.
+func getRankTier(app *GlobalAppData, region, puuid string) (string, error) {
+	platform := strings.ToLower(region)
+	cacheKey := fmt.Sprintf("ranktier:%s:%s", platform, puuid)
+
+	val, err := app.redisClient.Get(context.Background(), cacheKey).Result()
+	if err == redis.Nil {
+		url := fmt.Sprintf("https://%s.api.riotgames.com/lol/league/v4/entries/by-puuid/%s", platform, puuid)
+		req, _ := http.NewRequest("GET", url, nil)
+		req.Header.Set("X-Riot-Token", app.riotAPIKey)
+
+		// Get HTTP client from pool
+		client := riotClientPool.Get().(*http.Client)
+		defer riotClientPool.Put(client)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("failed to make league entries request: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			bodyBytes, _ := io.ReadAll(resp.Body)
+			return "", newRiotAPIError(riotEndpointLeague, resp, bodyBytes)
+		}
+
+		var entries []LeagueEntryDTO
+		if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+			return "", fmt.Errorf("failed to decode league entries response: %w", err)
+		}
+
+		tier := ""
+		for _, entry := range entries {
+			if entry.QueueType == "RANKED_SOLO_5x5" {
+				tier = entry.Tier
+				break
+			}
+			if entry.QueueType == "RANKED_FLEX_SR" {
+				tier = entry.Tier
+			}
+		}
+
+		// Unranked players are cached too, so they don't cost a request every time
+		go func(key, value string) {
+			cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = app.redisClient.Set(cacheCtx, key, value, rankTierCacheDuration).Err()
+		}(cacheKey, tier)
+
+		return tier, nil
+	} else if err != nil {
+		return "", fmt.Errorf("failed to get rank tier from cache: %w", err)
+	}
+	return val, nil
+}
+
+// rankTierWithBudget looks up the rank tier stored with a refreshed first page, drawing on
+// budget like the refresh's other Riot calls. A failed lookup leaves the tier unknown.
+func rankTierWithBudget(ctx context.Context, app *GlobalAppData, budget fetchBudget, region, puuid string) string {
+	if err := budget.acquire(ctx); err != nil {
+		return ""
+	}
+	defer budget.release()
+	tier, err := getRankTier(app, region, puuid)
+	if err != nil {
+		log.Printf("Could not get rank tier for %s: %v", puuid, err)
+	}
+	return tier
+}
+
 func extractPlayerMatchStats(matchData *MatchDto, playerPUUID string, app *GlobalAppData) (*PlayerMatchStats, error) {
+	if matchData == nil {
+		return nil, fmt.Errorf("matchData is nil")
+	}
+	return extractPlayerMatchStatsWithScores(matchData, playerPUUID, app, scoreMatchParticipants(&matchData.Info, getPerformanceScoreWeights()))
+}
+
+// extractPlayerMatchStatsWithScores is extractPlayerMatchStats with the lobby already scored,
+// for callers extracting several participants of one match
+func extractPlayerMatchStatsWithScores(matchData *MatchDto, playerPUUID string, app *GlobalAppData, scores map[string]ParticipantScore) (*PlayerMatchStats, error) {
 	if matchData == nil || matchData.Info.Participants == nil {
 		return nil, fmt.Errorf("matchData or participants list is nil for match %s", matchData.Metadata.MatchID)
 	}
@@ -270,24 +417,55 @@ func extractPlayerMatchStats(matchData *MatchDto, playerPUUID string, app *Globa
 		TeamPosition:       playerParticipant.TeamPosition,
 		Items:              []int{playerParticipant.Item0, playerParticipant.Item1, playerParticipant.Item2, playerParticipant.Item3, playerParticipant.Item4, playerParticipant.Item5, playerParticipant.Item6},
 		SummonerSpells:     []int{playerParticipant.Summoner1Id, playerParticipant.Summoner2Id},
+		SummonerSpellCasts: []int{playerParticipant.Summoner1Casts, playerParticipant.Summoner2Casts},
 		ChampLevel:         playerParticipant.ChampLevel,
 		DamageToTurrets:    playerParticipant.DamageDealtToTurrets,
 		DamageToObjectives: playerParticipant.DamageDealtToObjectives,
 		DamageToChampions:  playerParticipant.TotalDamageDealtToChampions,
 		TotalDamageTaken:   playerParticipant.TotalDamageTaken,
+		HealsOnTeammates:   playerParticipant.TotalHealsOnTeammates,
+		ShieldsOnTeammates: playerParticipant.TotalDamageShieldedOnTeammates,
 		TeamID:             playerParticipant.TeamID,
 		QueueID:            matchData.Info.QueueID,
+		Patch:              patchFromGameVersion(matchData.Info.GameVersion),
 	}
 
 	if playerParticipant.Perks != nil && len(playerParticipant.Perks.Styles) > 0 {
+		page := &RunePage{Perks: []int{}, StatPerks: playerParticipant.Perks.StatPerks}
+		var secondaryPerks []int
 		for _, style := range playerParticipant.Perks.Styles {
-			if style.Description == "primaryStyle" && len(style.Selections) > 0 {
-				stats.PrimaryRune = style.Selections[0].Perk
+			if style.Description == "primaryStyle" {
+				if len(style.Selections) > 0 {
+					stats.PrimaryRune = style.Selections[0].Perk
+				}
+				page.PrimaryStyle = style.Style
+				for _, selection := range style.Selections {
+					page.Perks = append(page.Perks, selection.Perk)
+				}
 			}
 			if style.Description == "subStyle" {
 				stats.SecondaryStyle = style.Style
+				page.SubStyle = style.Style
+				for _, selection := range style.Selections {
+					secondaryPerks = append(secondaryPerks, selection.Perk)
+				}
 			}
 		}
+		page.Perks = append(page.Perks, secondaryPerks...)
+		stats.RunePage = page
+	}
+
+	if isArenaMode(matchData.Info.GameMode) {
+		stats.Arena = extractArenaMatchStats(matchData, playerParticipant)
+	}
+	if isAramMode(matchData.Info.GameMode) {
+		stats.Aram = extractAramMatchStats(app.staticData, playerParticipant)
+	}
+
+	if score, ok := scores[playerPUUID]; ok {
+		stats.PerformanceScore = score.Score
+		stats.PerformanceGrade = score.Grade
+		stats.PerformanceBadge = score.Badge
 	}
 
 	return stats, nil
@@ -304,8 +482,51 @@ func getConcurrencyLimit() int {
 	return defaultConcurrencyLimit
 }
 
+// fetchBudget caps concurrent Riot calls shared by several fetches, e.g. every player of a
+// batch lookup. A nil budget imposes no limit beyond each fetch's own.
+type fetchBudget chan struct{}
+
+func newFetchBudget(size int) fetchBudget {
+	if size < 1 {
+		size = 1
+	}
+	return make(fetchBudget, size)
+}
+
+// acquire blocks until a slot is free or ctx is done
+func (b fetchBudget) acquire(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+	select {
+	case b <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b fetchBudget) release() {
+	if b != nil {
+		<-b
+	}
+}
+
 // fetchMatchesConcurrently fetches match details concurrently using errgroup with tunable concurrency
 func fetchMatchesConcurrently(app *GlobalAppData, region string, ids []string, puuid string) []PlayerMatchStats {
+	return fetchMatchesWithBudget(app, nil, region, ids, puuid, nil)
+}
+
+// fetchMatchesConcurrentlyWithProgress behaves like fetchMatchesConcurrently and additionally
+// calls onProgress after every match with its extracted stats, or nil if the match failed.
+// onProgress is called from the fetching goroutines and must be safe for concurrent use.
+func fetchMatchesConcurrentlyWithProgress(app *GlobalAppData, region string, ids []string, puuid string, onProgress func(stats *PlayerMatchStats)) []PlayerMatchStats {
+	return fetchMatchesWithBudget(app, nil, region, ids, puuid, onProgress)
+}
+
+// fetchMatchesWithBudget is fetchMatchesConcurrentlyWithProgress with every match fetch also
+// holding a slot of budget
+func fetchMatchesWithBudget(app *GlobalAppData, budget fetchBudget, region string, ids []string, puuid string, onProgress func(stats *PlayerMatchStats)) []PlayerMatchStats {
 	g, ctx := errgroup.WithContext(context.Background())
 	g.SetLimit(getConcurrencyLimit()) // tune until you hit Riot's global rate-limit
 
@@ -322,17 +543,30 @@ func fetchMatchesConcurrently(app *GlobalAppData, region string, ids []string, p
 			default:
 			}
 
+			if err := budget.acquire(ctx); err != nil {
+				return err
+			}
 			match, err := getMatchDetails(app, region, id)
+			budget.release()
 			if err != nil || match == nil {
+				if onProgress != nil {
+					onProgress(nil)
+				}
 				return nil // Don't fail the entire group
 			}
 
 			stats, err := extractPlayerMatchStats(match, puuid, app)
 			if err != nil || stats == nil {
+				if onProgress != nil {
+					onProgress(nil)
+				}
 				return nil
 			}
 
 			matchChan <- *stats
+			if onProgress != nil {
+				onProgress(stats)
+			}
 			return nil
 		})
 	}
@@ -358,10 +592,20 @@ func fetchMatchesConcurrently(app *GlobalAppData, region string, ids []string, p
 }
 
 func fetchAndStoreUserPerformance(app *GlobalAppData, userRegion, gameName, tagLine string, count, queueID, offset int) (*UserPerformance, error) {
+	return fetchAndStoreUserPerformanceWithBudget(app, nil, userRegion, gameName, tagLine, count, queueID, offset)
+}
+
+// fetchAndStoreUserPerformanceWithBudget is fetchAndStoreUserPerformance with its Riot calls
+// drawing on a budget shared with other fetches
+func fetchAndStoreUserPerformanceWithBudget(app *GlobalAppData, budget fetchBudget, userRegion, gameName, tagLine string, count, queueID, offset int) (*UserPerformance, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout*time.Duration(count+5))
 	defer cancel()
 
+	if err := budget.acquire(ctx); err != nil {
+		return nil, err
+	}
 	puuid, err := getPUUID(app, userRegion, gameName, tagLine)
+	budget.release()
 	if err != nil {
 		return nil, fmt.Errorf("error getting PUUID: %w", err)
 	}
@@ -386,12 +630,12 @@ func fetchAndStoreUserPerformance(app *GlobalAppData, userRegion, gameName, tagL
 
 	collection := app.mongoClient.Database(app.mongoDatabase).Collection("userperformances")
 	var cachedPerformance UserPerformance
-	cacheKeyDB := fmt.Sprintf("%s_%s", userRegion, puuid)
-	redisCacheKey := fmt.Sprintf("userperformance:%s:o%d", cacheKeyDB, offset)
+	redisCacheKey := userPerformanceRedisKey(userRegion, puuid, offset)
 	val, err := app.redisClient.Get(ctx, redisCacheKey).Result()
 	if err == nil {
 		if err := json.Unmarshal([]byte(val), &cachedPerformance); err == nil {
 			log.Printf("User performance for %s loaded from Redis cache with offset %d.", puuid, offset)
+			refreshRiotID(app, &cachedPerformance, gameName, tagLine)
 			if len(cachedPerformance.Matches) >= count {
 				if len(cachedPerformance.Matches) > count {
 					trimmed := *&cachedPerformance
@@ -410,6 +654,7 @@ func fetchAndStoreUserPerformance(app *GlobalAppData, userRegion, gameName, tagL
 		err = collection.FindOne(ctx, bson.M{"_id": puuid, "region": userRegion}).Decode(&cachedPerformance)
 		if err == nil && len(cachedPerformance.Matches) >= count && time.Now().Unix()-cachedPerformance.UpdatedAt < int64(userPerformanceCacheDuration/time.Second)/2 {
 			log.Printf("User performance for %s loaded from MongoDB.", puuid)
+			refreshRiotID(app, &cachedPerformance, gameName, tagLine)
 
 			// Move Redis caching off the critical path - run asynchronously
 			go func(key string, data UserPerformance) {
@@ -436,7 +681,11 @@ func fetchAndStoreUserPerformance(app *GlobalAppData, userRegion, gameName, tagL
 
 	var seasonStartTime int64 = 0
 
+	if err := budget.acquire(ctx); err != nil {
+		return nil, err
+	}
 	matchIDs, err := getMatchIDs(app, userRegion, puuid, count, queueID, seasonStartTime, offset)
+	budget.release()
 	if err != nil {
 		return nil, fmt.Errorf("error getting match IDs: %w", err)
 	}
@@ -451,7 +700,7 @@ func fetchAndStoreUserPerformance(app *GlobalAppData, userRegion, gameName, tagL
 	}
 
 	var matches []PlayerMatchStats
-	matches = fetchMatchesConcurrently(app, userRegion, matchIDs, puuid)
+	matches = fetchMatchesWithBudget(app, budget, userRegion, matchIDs, puuid, nil)
 
 	performance := UserPerformance{
 		PUUID:     puuid,
@@ -463,32 +712,10 @@ func fetchAndStoreUserPerformance(app *GlobalAppData, userRegion, gameName, tagL
 
 	// Only cache in MongoDB for offset 0 (first page)
 	if offset == 0 {
+		// Benchmarks bucket the stored population by tier, so record it alongside the matches
+		performance.RankTier = rankTierWithBudget(ctx, app, budget, userRegion, puuid)
 		// Move persistence off the critical path - run asynchronously
-		go func(data UserPerformance, redisKey string, collection *mongo.Collection) {
-			persistCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-
-			// Validate data before MongoDB write to prevent injection
-			if err := ValidatePUUID(data.PUUID); err != nil {
-				log.Printf("Invalid PUUID in async write, skipping: %v", err)
-				return
-			}
-			if err := ValidateRegion(data.Region); err != nil {
-				log.Printf("Invalid region in async write, skipping: %v", err)
-				return
-			}
-
-			// Async MongoDB write
-			opts := options.Update().SetUpsert(true)
-			filter := bson.M{"_id": data.PUUID, "region": data.Region}
-			update := bson.M{"$set": data}
-			_, _ = collection.UpdateOne(persistCtx, filter, update, opts)
-
-			// Async Redis write
-			if dataJSON, err := json.Marshal(data); err == nil {
-				_ = app.redisClient.Set(persistCtx, redisKey, dataJSON, userPerformanceCacheDuration).Err()
-			}
-		}(performance, redisCacheKey, collection)
+		go persistUserPerformance(app, performance, redisCacheKey)
 	} else {
 		// For paginated requests, only cache in Redis with shorter TTL
 		go func(data UserPerformance, redisKey string) {
@@ -503,6 +730,38 @@ func fetchAndStoreUserPerformance(app *GlobalAppData, userRegion, gameName, tagL
 	return &performance, nil
 }
 
+// userPerformanceRedisKey returns the Redis key used to cache a page of user performance
+func userPerformanceRedisKey(region, puuid string, offset int) string {
+	return fmt.Sprintf("userperformance:%s_%s:o%d", region, puuid, offset)
+}
+
+// persistUserPerformance writes first-page user performance to MongoDB and Redis. Callers fill
+// in RankTier; an empty one leaves the stored tier as it was.
+func persistUserPerformance(app *GlobalAppData, data UserPerformance, redisKey string) {
+	// Validate data before MongoDB write to prevent injection
+	if err := ValidatePUUID(data.PUUID); err != nil {
+		log.Printf("Invalid PUUID in async write, skipping: %v", err)
+		return
+	}
+	if err := ValidateRegion(data.Region); err != nil {
+		log.Printf("Invalid region in async write, skipping: %v", err)
+		return
+	}
+
+	persistCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection := app.mongoClient.Database(app.mongoDatabase).Collection("userperformances")
+	opts := options.Update().SetUpsert(true)
+	filter := bson.M{"_id": data.PUUID, "region": data.Region}
+	update := bson.M{"$set": data}
+	_, _ = collection.UpdateOne(persistCtx, filter, update, opts)
+
+	if dataJSON, err := json.Marshal(data); err == nil {
+		_ = app.redisClient.Set(persistCtx, redisKey, dataJSON, userPerformanceCacheDuration).Err()
+	}
+}
+
 func loadDataDragonVersions(app *GlobalAppData) ([]string, error) {
 	url := fmt.Sprintf("%s/api/versions.json", dataDragonBaseURL)
 	req, _ := http.NewRequest("GET", url, nil)
@@ -661,14 +920,15 @@ func loadSummonerSpells(app *GlobalAppData, version string) (map[string]Summoner
 	return spells.Data, nil
 }
 
-func loadRunes(app *GlobalAppData, version string) (map[int]RuneInfo, error) {
+func loadRunes(app *GlobalAppData, version string) (map[int]RuneInfo, map[int]RunePathData, error) {
 	cacheKey := fmt.Sprintf("ddragon:runesreforged:%s", version)
 	val, err := app.redisClient.Get(context.Background(), cacheKey).Result()
 	if err == nil {
 		var runePaths []RunePathData
 		if json.Unmarshal([]byte(val), &runePaths) == nil {
 			log.Printf("Runes loaded from cache for version %s", version)
-			return flattenRuneData(runePaths), nil
+			runes, paths := flattenRuneData(runePaths)
+			return runes, paths, nil
 		}
 	}
 
@@ -681,18 +941,18 @@ func loadRunes(app *GlobalAppData, version string) (map[int]RuneInfo, error) {
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch runes for version %s: %w", version, err)
+		return nil, nil, fmt.Errorf("failed to fetch runes for version %s: %w", version, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("runes request for version %s failed with status %d", version, resp.StatusCode)
+		return nil, nil, fmt.Errorf("runes request for version %s failed with status %d", version, resp.StatusCode)
 	}
 
 	bodyBytes, _ := io.ReadAll(resp.Body)
 	var runePaths []RunePathData
 	if err := json.Unmarshal(bodyBytes, &runePaths); err != nil {
-		return nil, fmt.Errorf("failed to decode runes for version %s: %w", version, err)
+		return nil, nil, fmt.Errorf("failed to decode runes for version %s: %w", version, err)
 	}
 
 	// Move Redis caching off the critical path - run asynchronously
@@ -703,19 +963,125 @@ func loadRunes(app *GlobalAppData, version string) (map[int]RuneInfo, error) {
 	}(cacheKey, bodyBytes)
 
 	log.Printf("Runes loaded from API for version %s", version)
-	return flattenRuneData(runePaths), nil
+	runes, paths := flattenRuneData(runePaths)
+	return runes, paths, nil
+}
+
+// loadQueues fetches Riot's queue catalog. It isn't versioned with Data Dragon, so it is cached
+// under a single key and refreshed when the cache expires.
+func loadQueues(app *GlobalAppData) (map[int]QueueInfo, error) {
+	cacheKey := "static:queues"
+	val, err := app.redisClient.Get(context.Background(), cacheKey).Result()
+	if err == nil {
+		var queues []QueueDto
+		if json.Unmarshal([]byte(val), &queues) == nil {
+			log.Println("Queues loaded from cache")
+			return buildQueueCatalog(queues), nil
+		}
+	}
+
+	url := fmt.Sprintf("%s/queues.json", riotStaticDocsBaseURL)
+	req, _ := http.NewRequest("GET", url, nil)
+
+	// Get HTTP client from pool
+	client := riotClientPool.Get().(*http.Client)
+	defer riotClientPool.Put(client)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch queues: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("queues request failed with status %d", resp.StatusCode)
+	}
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	var queues []QueueDto
+	if err := json.Unmarshal(bodyBytes, &queues); err != nil {
+		return nil, fmt.Errorf("failed to decode queues: %w", err)
+	}
+
+	// Move Redis caching off the critical path - run asynchronously
+	go func(key string, data []byte) {
+		cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = app.redisClient.Set(cacheCtx, key, string(data), staticDataCacheDuration).Err()
+	}(cacheKey, bodyBytes)
+
+	log.Println("Queues loaded from API")
+	return buildQueueCatalog(queues), nil
+}
+
+// loadArenaAugments fetches Arena augments, which Data Dragon doesn't publish, from CommunityDragon.
+// Like the queue catalog it is cached under a single key rather than per Data Dragon version.
+func loadArenaAugments(app *GlobalAppData) (map[int]ArenaAugmentData, error) {
+	cacheKey := "cdragon:arenaaugments"
+	val, err := app.redisClient.Get(context.Background(), cacheKey).Result()
+	if err == nil {
+		var arena CommunityDragonArena
+		if json.Unmarshal([]byte(val), &arena) == nil {
+			log.Println("Arena augments loaded from cache")
+			return indexArenaAugments(arena.Augments), nil
+		}
+	}
+
+	url := fmt.Sprintf("%s/cdragon/arena/en_us.json", communityDragonBaseURL)
+	req, _ := http.NewRequest("GET", url, nil)
+
+	// Get HTTP client from pool
+	client := riotClientPool.Get().(*http.Client)
+	defer riotClientPool.Put(client)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch arena augments: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("arena augments request failed with status %d", resp.StatusCode)
+	}
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	var arena CommunityDragonArena
+	if err := json.Unmarshal(bodyBytes, &arena); err != nil {
+		return nil, fmt.Errorf("failed to decode arena augments: %w", err)
+	}
+
+	// Move Redis caching off the critical path - run asynchronously
+	go func(key string, data []byte) {
+		cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = app.redisClient.Set(cacheCtx, key, string(data), staticDataCacheDuration).Err()
+	}(cacheKey, bodyBytes)
+
+	log.Println("Arena augments loaded from API")
+	return indexArenaAugments(arena.Augments), nil
+}
+
+func indexArenaAugments(augments []ArenaAugmentData) map[int]ArenaAugmentData {
+	byID := make(map[int]ArenaAugmentData, len(augments))
+	for _, augment := range augments {
+		byID[augment.ID] = augment
+	}
+	return byID
 }
 
-func flattenRuneData(runePaths []RunePathData) map[int]RuneInfo {
+// flattenRuneData indexes every rune by ID, and every rune tree by its style ID
+func flattenRuneData(runePaths []RunePathData) (map[int]RuneInfo, map[int]RunePathData) {
 	flatRunes := make(map[int]RuneInfo)
+	pathsByID := make(map[int]RunePathData, len(runePaths))
 	for _, path := range runePaths {
+		pathsByID[path.ID] = path
 		for _, slot := range path.Slots {
 			for _, runeInfo := range slot.Runes {
 				flatRunes[runeInfo.ID] = runeInfo
 			}
 		}
 	}
-	return flatRunes
+	return flatRunes, pathsByID
 }
 
 func populateStaticData(app *GlobalAppData) error {
@@ -751,16 +1117,33 @@ func populateStaticData(app *GlobalAppData) error {
 		summonerSpellsByKey[spell.Key] = spell
 	}
 
-	runes, err := loadRunes(app, latestVersion)
+	runes, runePaths, err := loadRunes(app, latestVersion)
 	if err != nil {
 		return fmt.Errorf("error loading runes: %w", err)
 	}
 
+	// The queue catalog lives outside Data Dragon; without it queue filters are refused until
+	// validateQueueIDParam manages to load it
+	queues, err := loadQueues(app)
+	if err != nil {
+		log.Printf("Warning: error loading queues, queue filters are unavailable: %v", err)
+		queues = map[int]QueueInfo{}
+	}
+
+	augments, err := loadArenaAugments(app)
+	if err != nil {
+		log.Printf("Warning: error loading arena augments, augment names will be missing: %v", err)
+		augments = map[int]ArenaAugmentData{}
+	}
+
 	app.staticData = &StaticData{
 		Champions:      championKeyToDataMap,
 		Items:          items,
 		Runes:          runes,
+		RunePaths:      runePaths,
 		SummonerSpells: summonerSpellsByKey,
+		Queues:         queues,
+		Augments:       augments,
 		LatestVersion:  latestVersion,
 	}
 	log.Println("Static data populated successfully.")
@@ -784,12 +1167,21 @@ func getAPIRegion(region string) string {
 	}
 }
 
+// patchFromGameVersion trims a game version like "14.23.636.4001" to its patch, "14.23"
+func patchFromGameVersion(gameVersion string) string {
+	parts := strings.SplitN(gameVersion, ".", 3)
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[0] + "." + parts[1]
+}
+
 // Add this helper function to check if a game mode is classic
 func isClassicMode(gameMode string) bool {
 	return strings.ToUpper(gameMode) == "CLASSIC"
 }
 
-func calculateRecentGamesSummary(matches []PlayerMatchStats, puuid, region, riotID string) *RecentGamesSummary {
+func calculateRecentGamesSummary(sd *StaticData, matches []PlayerMatchStats, puuid, region, riotID string) *RecentGamesSummary {
 	if len(matches) == 0 {
 		return &RecentGamesSummary{
 			PUUID:         puuid,
@@ -799,6 +1191,7 @@ func calculateRecentGamesSummary(matches []PlayerMatchStats, puuid, region, riot
 			OverallStats:  OverallStats{},
 			RoleStats:     make(map[string]RoleStats),
 			ChampionStats: make(map[string]ChampionStats),
+			QueueStats:    make(map[string]QueueStats),
 			RecentMatches: []PlayerMatchStats{},
 			LastUpdated:   time.Now().Unix(),
 		}
@@ -821,6 +1214,10 @@ func calculateRecentGamesSummary(matches []PlayerMatchStats, puuid, region, riot
 		OverallStats:  overallStats,
 		RoleStats:     roleStats,
 		ChampionStats: championStats,
+		SpellStats:    calculateSummonerSpellStats(sd, matches),
+		QueueStats:    calculateQueueStats(sd, matches),
+		ArenaStats:    calculateArenaSummary(sd, matches),
+		AramStats:     calculateAramSummary(sd, matches),
 		RecentMatches: matches,
 		LastUpdated:   time.Now().Unix(),
 	}
@@ -920,6 +1317,8 @@ func calculateRoleStats(matches []PlayerMatchStats) map[string]RoleStats {
 		var totalVisionScore, totalDamage int64
 		var totalKillParticipation float64
 		var totalGameTime int64
+		var totalScore float64
+		var scoredGames, mvpCount, aceCount int
 
 		// Separate tracking for classic mode stats
 		var classicGameTime, classicCS, classicGold int64
@@ -937,6 +1336,18 @@ func calculateRoleStats(matches []PlayerMatchStats) map[string]RoleStats {
 			totalKillParticipation += match.KillParticipation
 			totalGameTime += match.GameDuration
 
+			// Matches stored before scoring was added have no grade and don't count
+			if match.PerformanceGrade != "" {
+				totalScore += match.PerformanceScore
+				scoredGames++
+			}
+			switch match.PerformanceBadge {
+			case performanceBadgeMVP:
+				mvpCount++
+			case performanceBadgeACE:
+				aceCount++
+			}
+
 			// Only count CS and Gold for classic mode
 			if isClassicMode(match.GameMode) {
 				classicGameTime += match.GameDuration
@@ -956,6 +1367,11 @@ func calculateRoleStats(matches []PlayerMatchStats) map[string]RoleStats {
 			roleKDA = float64(totalKills + totalAssists)
 		}
 
+		var avgPerformanceScore float64
+		if scoredGames > 0 {
+			avgPerformanceScore = totalScore / float64(scoredGames)
+		}
+
 		// Calculate CS/min and Gold/min only for classic games
 		var avgCSPerMin, avgGoldPerMin float64
 		if classicGameTime > 0 {
@@ -981,6 +1397,9 @@ func calculateRoleStats(matches []PlayerMatchStats) map[string]RoleStats {
 			AvgGoldPerMin:        avgGoldPerMin,
 			AvgDamageToChampions: float64(totalDamage) / float64(len(roleMatches)),
 			AvgKillParticipation: totalKillParticipation / float64(len(roleMatches)),
+			AvgPerformanceScore:  avgPerformanceScore,
+			MVPCount:             mvpCount,
+			ACECount:             aceCount,
 		}
 	}
 
@@ -1006,6 +1425,8 @@ func calculateChampionStats(matches []PlayerMatchStats) map[string]ChampionStats
 		var totalVisionScore, totalDamage int64
 		var totalKillParticipation float64
 		var totalGameTime int64
+		var totalScore float64
+		var scoredGames, mvpCount, aceCount int
 		var bestKDA, worstKDA float64
 		var lastPlayed int64
 		var championID int
@@ -1038,6 +1459,18 @@ func calculateChampionStats(matches []PlayerMatchStats) map[string]ChampionStats
 			totalKillParticipation += match.KillParticipation
 			totalGameTime += match.GameDuration
 
+			// Matches stored before scoring was added have no grade and don't count
+			if match.PerformanceGrade != "" {
+				totalScore += match.PerformanceScore
+				scoredGames++
+			}
+			switch match.PerformanceBadge {
+			case performanceBadgeMVP:
+				mvpCount++
+			case performanceBadgeACE:
+				aceCount++
+			}
+
 			// Only count CS and Gold for classic mode
 			if isClassicMode(match.GameMode) {
 				classicGameTime += match.GameDuration
@@ -1065,6 +1498,11 @@ func calculateChampionStats(matches []PlayerMatchStats) map[string]ChampionStats
 			championKDA = float64(totalKills + totalAssists)
 		}
 
+		var avgPerformanceScore float64
+		if scoredGames > 0 {
+			avgPerformanceScore = totalScore / float64(scoredGames)
+		}
+
 		// Calculate CS/min and Gold/min only for classic games
 		var avgCSPerMin, avgGoldPerMin float64
 		if classicGameTime > 0 {
@@ -1093,6 +1531,9 @@ func calculateChampionStats(matches []PlayerMatchStats) map[string]ChampionStats
 			AvgGoldPerMin:        avgGoldPerMin,
 			AvgDamageToChampions: float64(totalDamage) / float64(len(championMatches)),
 			AvgKillParticipation: totalKillParticipation / float64(len(championMatches)),
+			AvgPerformanceScore:  avgPerformanceScore,
+			MVPCount:             mvpCount,
+			ACECount:             aceCount,
 			LastPlayed:           lastPlayed,
 		}
 	}
@@ -1139,7 +1580,8 @@ func fetchRecentGamesSummary(app *GlobalAppData, userRegion, gameName, tagLine s
 	}
 
 	// Calculate comprehensive summary
-	summary := calculateRecentGamesSummary(userPerformance.Matches, userPerformance.PUUID, userPerformance.Region, userPerformance.RiotID)
+	summary := calculateRecentGamesSummary(app.staticData, userPerformance.Matches, userPerformance.PUUID, userPerformance.Region, userPerformance.RiotID)
+	annotatePercentiles(context.Background(), app, summary, userPerformance.RankTier)
 
 	return summary, nil
 }
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	legacyUsageKeyPrefix   = "legacyusage:"
+	legacyUsageTTL         = 90 * 24 * time.Hour
+	legacyUsageReportDays  = 30
+	defaultLegacySunset    = "2027-04-30"
+	legacyDeprecationStart = "2026-10-18"
+)
+
+// Legacy routes superseded by the dashboard endpoint. /api/v2 does not serve them.
+var legacyRoutes = []string{"matches", "summary"}
+
+// registerV1Routes mounts the v1 API. It is served both under /api/v1 and, for existing
+// clients, unversioned under /api.
+func registerV1Routes(api chi.Router, app *GlobalAppData) {
+	players := api.With(renamedRiotIDMiddleware(app))
+
+	// New consolidated dashboard endpoint that combines matches and summary
+	players.Get("/player/{region}/{gameName}/{tagLine}/dashboard", getPlayerDashboardHandler(app))
+	registerPlayerRoutes(api, app)
+
+	// Legacy endpoints (kept for backward compatibility during transition)
+	players.With(legacyRouteMiddleware(app, "matches")).Get("/player/{region}/{gameName}/{tagLine}/matches", getPlayerPerformanceHandler(app))
+	players.With(legacyRouteMiddleware(app, "summary")).Get("/player/{region}/{gameName}/{tagLine}/summary", getRecentGamesSummaryHandler(app))
+
+	registerSharedRoutes(api, app)
+}
+
+// registerV2Routes mounts the v2 API: the dashboard resolves IDs against static data and the
+// legacy matches/summary endpoints are gone
+func registerV2Routes(api chi.Router, app *GlobalAppData) {
+	api.With(renamedRiotIDMiddleware(app)).Get("/player/{region}/{gameName}/{tagLine}/dashboard", getPlayerDashboardV2Handler(app))
+	registerPlayerRoutes(api, app)
+	registerSharedRoutes(api, app)
+}
+
+// registerPlayerRoutes mounts the per-player routes whose shape is the same in every version.
+// Routes by Riot ID redirect old Riot IDs to the current one; routes by PUUID redirect to the
+// route for the current Riot ID.
+func registerPlayerRoutes(api chi.Router, app *GlobalAppData) {
+	players := api.With(renamedRiotIDMiddleware(app))
+	players.Get("/player/{region}/{gameName}/{tagLine}/dashboard/stream", getPlayerDashboardStreamHandler(app))
+	players.Get("/player/{region}/{gameName}/{tagLine}/builds", getPlayerBuildsHandler(app))
+	players.Get("/player/{region}/{gameName}/{tagLine}/runes", getPlayerRunesHandler(app))
+	players.Get("/player/{region}/{gameName}/{tagLine}/heatmap", getPlayerHeatmapHandler(app))
+	players.Get("/player/{region}/{gameName}/{tagLine}/export", getPlayerExportHandler(app))
+
+	api.Get("/player/by-puuid/{region}/{puuid}", getPlayerByPUUIDHandler(app))
+	api.Get("/player/by-puuid/{region}/{puuid}/*", redirectPUUIDRouteHandler(app))
+
+	// Several players at once, e.g. a whole lobby
+	api.Post("/players/batch", batchPlayersHandler(app))
+}
+
+// registerSharedRoutes mounts the non-player routes whose shape is the same in every version
+func registerSharedRoutes(api chi.Router, app *GlobalAppData) {
+	api.Get("/static-data", getStaticDataHandler(app))
+	api.Get("/search", searchHandler(app))
+	api.Get("/match/{region}/{matchId}", getMatchDetailsHandler(app))
+	api.Get("/match/{region}/{matchId}/scoreboard", getMatchScoreboardHandler(app))
+
+	api.Get("/popular-items", getPopularItemsHandler(app))
+	api.Get("/stats/items", getItemStatsHandler(app))
+	api.Get("/champions/stats", getChampionStatsHandler(app))
+
+	// Tracked players refreshed by the background scheduler. Each one spends background Riot
+	// budget, so only admins may change the list.
+	api.Get("/tracked", getTrackedPlayersHandler(app))
+	api.With(adminTokenMiddleware).Post("/tracked", addTrackedPlayerHandler(app))
+	api.With(adminTokenMiddleware).Delete("/tracked/{region}/{gameName}/{tagLine}", removeTrackedPlayerHandler(app))
+
+	// Background fetch jobs with progress reporting
+	api.Post("/jobs/fetch", createFetchJobHandler(app))
+	api.Get("/jobs/{jobId}", getFetchJobHandler(app))
+}
+
+// getLegacySunset returns when legacy routes may be removed, from LEGACY_ENDPOINT_SUNSET (YYYY-MM-DD)
+func getLegacySunset() time.Time {
+	if sunsetStr := os.Getenv("LEGACY_ENDPOINT_SUNSET"); sunsetStr != "" {
+		if sunset, err := time.Parse("2006-01-02", sunsetStr); err == nil {
+			return sunset
+		}
+		log.Printf("Invalid LEGACY_ENDPOINT_SUNSET %q, using %s", sunsetStr, defaultLegacySunset)
+	}
+	sunset, _ := time.Parse("2006-01-02", defaultLegacySunset)
+	return sunset
+}
+
+// legacySuccessorPath maps a legacy matches/summary path to the v2 dashboard for the same player
+func legacySuccessorPath(path string) string {
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(path, "/api"), "/v1")
+	if i := strings.LastIndex(trimmed, "/"); i >= 0 {
+		trimmed = trimmed[:i]
+	}
+	return "/api/v2" + trimmed + "/dashboard"
+}
+
+// legacyRouteMiddleware marks a route as deprecated (RFC 9745 Deprecation, RFC 8594 Sunset)
+// and counts its calls so we know when it is safe to remove
+func legacyRouteMiddleware(app *GlobalAppData, route string) func(http.Handler) http.Handler {
+	deprecated, _ := time.Parse("2006-01-02", legacyDeprecationStart)
+	deprecationHeader := "@" + strconv.FormatInt(deprecated.Unix(), 10)
+	sunsetHeader := getLegacySunset().UTC().Format(http.TimeFormat)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", deprecationHeader)
+			w.Header().Set("Sunset", sunsetHeader)
+			w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", legacySuccessorPath(r.URL.EscapedPath())))
+			recordLegacyUsage(app, route)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func legacyUsageKey(route string, day time.Time) string {
+	return legacyUsageKeyPrefix + route + ":" + day.UTC().Format("2006-01-02")
+}
+
+// recordLegacyUsage bumps today's counter for route without delaying the request
+func recordLegacyUsage(app *GlobalAppData, route string) {
+	if app.redisClient == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		key := legacyUsageKey(route, time.Now())
+		pipe := app.redisClient.TxPipeline()
+		pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, legacyUsageTTL)
+		if _, err := pipe.Exec(ctx); err != nil {
+			log.Printf("Warning: Failed to record legacy usage for %s: %v", route, err)
+		}
+	}()
+}
+
+// getLegacyUsageHandler reports daily call counts for each legacy route over the last 30 days
+func getLegacyUsageHandler(app *GlobalAppData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if app.redisClient == nil {
+			writeAPIError(w, r, newAPIError(http.StatusServiceUnavailable, ErrCodeServerMisconfigured, "Usage tracking requires Redis"))
+			return
+		}
+
+		sunset := getLegacySunset().UTC().Format(http.TimeFormat)
+		now := time.Now()
+		usage := make([]LegacyRouteUsage, 0, len(legacyRoutes))
+		for _, route := range legacyRoutes {
+			keys := make([]string, legacyUsageReportDays)
+			for i := range keys {
+				keys[i] = legacyUsageKey(route, now.AddDate(0, 0, -i))
+			}
+			values, err := app.redisClient.MGet(r.Context(), keys...).Result()
+			if err != nil {
+				writeError(w, r, err, "Error reading legacy usage")
+				return
+			}
+
+			entry := LegacyRouteUsage{Route: route, Sunset: sunset, PerDay: map[string]int64{}}
+			for i, value := range values {
+				countStr, ok := value.(string)
+				if !ok {
+					continue
+				}
+				count, _ := strconv.ParseInt(countStr, 10, 64)
+				day := strings.TrimPrefix(keys[i], legacyUsageKeyPrefix+route+":")
+				entry.PerDay[day] = count
+				entry.Total += count
+				if day > entry.LastDay {
+					entry.LastDay = day
+				}
+			}
+			usage = append(usage, entry)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(usage); err != nil {
+			log.Printf("Error encoding legacy usage response: %v", err)
+		}
+	}
+}
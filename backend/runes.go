@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+const primaryRuneSelections = 4 // Keystone plus one rune from each of the three lower rows
+
+// runePageKey identifies a rune page by every selection, ignoring the per-game var values
+func runePageKey(page *RunePage) string {
+	return fmt.Sprintf("%d/%d/%v/%d-%d-%d", page.PrimaryStyle, page.SubStyle, page.Perks,
+		page.StatPerks.Offense, page.StatPerks.Flex, page.StatPerks.Defense)
+}
+
+// resolveRunePage turns a stored rune page into names and icons
+func resolveRunePage(sd *StaticData, page *RunePage) RunePageStats {
+	stats := RunePageStats{
+		PrimaryStyle:   resolveRunePathRef(sd, page.PrimaryStyle),
+		SubStyle:       resolveRunePathRef(sd, page.SubStyle),
+		PrimaryRunes:   []StaticRef{},
+		SecondaryRunes: []StaticRef{},
+		StatShards: []StaticRef{
+			resolveStatShardRef(page.StatPerks.Offense),
+			resolveStatShardRef(page.StatPerks.Flex),
+			resolveStatShardRef(page.StatPerks.Defense),
+		},
+	}
+
+	for i, perk := range page.Perks {
+		switch {
+		case i == 0:
+			stats.Keystone = resolveRuneRef(sd, perk)
+		case i < primaryRuneSelections:
+			stats.PrimaryRunes = append(stats.PrimaryRunes, resolveRuneRef(sd, perk))
+		default:
+			stats.SecondaryRunes = append(stats.SecondaryRunes, resolveRuneRef(sd, perk))
+		}
+	}
+
+	return stats
+}
+
+// aggregateChampionRunePages groups matches per champion and ranks each champion's
+// distinct rune pages by games played, then win rate
+func aggregateChampionRunePages(sd *StaticData, matches []PlayerMatchStats) map[string]ChampionRuneStats {
+	type pageAgg struct {
+		page  *RunePage
+		games int
+		wins  int
+	}
+	type championAgg struct {
+		championID int
+		games      int
+		pages      map[string]*pageAgg
+	}
+
+	byChampion := map[string]*championAgg{}
+	for _, match := range matches {
+		// Matches stored before full rune pages were recorded have no page
+		if match.RunePage == nil || len(match.RunePage.Perks) == 0 {
+			continue
+		}
+
+		agg, ok := byChampion[match.ChampionName]
+		if !ok {
+			agg = &championAgg{championID: match.ChampionID, pages: map[string]*pageAgg{}}
+			byChampion[match.ChampionName] = agg
+		}
+		agg.games++
+
+		key := runePageKey(match.RunePage)
+		p, ok := agg.pages[key]
+		if !ok {
+			p = &pageAgg{page: match.RunePage}
+			agg.pages[key] = p
+		}
+		p.games++
+		if match.Win {
+			p.wins++
+		}
+	}
+
+	result := make(map[string]ChampionRuneStats, len(byChampion))
+	for name, agg := range byChampion {
+		ranked := make([]*pageAgg, 0, len(agg.pages))
+		for _, p := range agg.pages {
+			ranked = append(ranked, p)
+		}
+		sort.Slice(ranked, func(i, j int) bool {
+			if ranked[i].games != ranked[j].games {
+				return ranked[i].games > ranked[j].games
+			}
+			return ranked[i].wins*ranked[j].games > ranked[j].wins*ranked[i].games
+		})
+
+		stats := ChampionRuneStats{
+			ChampionName:  name,
+			ChampionID:    agg.championID,
+			GamesAnalyzed: agg.games,
+			Pages:         make([]RunePageStats, 0, len(ranked)),
+		}
+		for _, p := range ranked {
+			page := resolveRunePage(sd, p.page)
+			page.Games = p.games
+			page.Wins = p.wins
+			page.WinRate = float64(p.wins) / float64(p.games) * 100
+			stats.Pages = append(stats.Pages, page)
+		}
+		result[name] = stats
+	}
+
+	return result
+}
+
+// fetchPlayerRunes analyzes the rune pages used over a player's recent matches
+func fetchPlayerRunes(app *GlobalAppData, region, gameName, tagLine string, count, queueID int) (*PlayerRunesResponse, error) {
+	performance, err := fetchAndStoreUserPerformance(app, region, gameName, tagLine, count, queueID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user performance: %w", err)
+	}
+
+	return &PlayerRunesResponse{
+		PUUID:     performance.PUUID,
+		Region:    performance.Region,
+		RiotID:    performance.RiotID,
+		Champions: aggregateChampionRunePages(app.staticData, performance.Matches),
+	}, nil
+}
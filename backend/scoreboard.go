@@ -0,0 +1,100 @@
+package main
+
+import (
+	"sort"
+)
+
+// buildMatchScoreboard normalizes every participant of a match into PlayerMatchStats-style rows
+// grouped by team, with static data resolved and team totals and shares computed.
+func buildMatchScoreboard(app *GlobalAppData, match *MatchDto) *MatchScoreboard {
+	sd := app.staticData
+	teamsByID := make(map[int]*ScoreboardTeam)
+	scores := scoreMatchParticipants(&match.Info, getPerformanceScoreWeights())
+
+	for _, p := range match.Info.Participants {
+		stats, err := extractPlayerMatchStatsWithScores(match, p.PUUID, app, scores)
+		if err != nil {
+			continue
+		}
+
+		team, ok := teamsByID[p.TeamID]
+		if !ok {
+			team = &ScoreboardTeam{TeamID: p.TeamID, Win: p.Win, Bans: []StaticRef{}}
+			teamsByID[p.TeamID] = team
+		}
+
+		row := ScoreboardParticipant{
+			PlayerMatchStats: *stats,
+			PUUID:            p.PUUID,
+			RiotIDGameName:   p.RiotIDGameName,
+			RiotIDTagline:    p.RiotIDTagline,
+			Champion:         resolveChampionRef(sd, p.ChampionID),
+			ItemRefs:         make([]StaticRef, 0, len(stats.Items)),
+			SpellRefs:        make([]StaticRef, 0, len(stats.SummonerSpells)),
+		}
+		for _, itemID := range stats.Items {
+			row.ItemRefs = append(row.ItemRefs, resolveItemRef(sd, itemID))
+		}
+		for _, spellID := range stats.SummonerSpells {
+			row.SpellRefs = append(row.SpellRefs, resolveSummonerSpellRef(sd, spellID))
+		}
+		if stats.PrimaryRune != 0 {
+			runeRef := resolveRuneRef(sd, stats.PrimaryRune)
+			row.PrimaryRuneRef = &runeRef
+		}
+
+		team.Kills += stats.Kills
+		team.Deaths += stats.Deaths
+		team.Assists += stats.Assists
+		team.GoldEarned += stats.GoldEarned
+		team.DamageToChampions += stats.DamageToChampions
+		team.TotalMinionsKilled += stats.TotalMinionsKilled
+		team.VisionScore += stats.VisionScore
+		team.Participants = append(team.Participants, row)
+	}
+
+	for i := range match.Info.Teams {
+		teamDto := match.Info.Teams[i]
+		team, ok := teamsByID[teamDto.TeamID]
+		if !ok {
+			continue
+		}
+		team.Win = teamDto.Win
+		team.Objectives = &teamDto.Objectives
+		for _, ban := range teamDto.Bans {
+			if ban.ChampionID > 0 {
+				team.Bans = append(team.Bans, resolveChampionRef(sd, ban.ChampionID))
+			}
+		}
+	}
+
+	scoreboard := &MatchScoreboard{
+		MatchID:      match.Metadata.MatchID,
+		GameMode:     match.Info.GameMode,
+		GameVersion:  match.Info.GameVersion,
+		GameCreation: match.Info.GameCreation,
+		GameDuration: match.Info.GameDuration,
+		QueueID:      match.Info.QueueID,
+		Teams:        make([]ScoreboardTeam, 0, len(teamsByID)),
+	}
+
+	for _, team := range teamsByID {
+		for i := range team.Participants {
+			row := &team.Participants[i]
+			if team.DamageToChampions > 0 {
+				row.DamageShare = float64(row.DamageToChampions) / float64(team.DamageToChampions)
+			}
+			if team.GoldEarned > 0 {
+				row.GoldShare = float64(row.GoldEarned) / float64(team.GoldEarned)
+			}
+		}
+		scoreboard.Teams = append(scoreboard.Teams, *team)
+	}
+
+	// Blue side (100) first
+	sort.Slice(scoreboard.Teams, func(i, j int) bool {
+		return scoreboard.Teams[i].TeamID < scoreboard.Teams[j].TeamID
+	})
+
+	return scoreboard
+}
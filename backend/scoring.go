@@ -0,0 +1,226 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	maxPerformanceScore = 10.0
+	performanceBadgeMVP = "MVP" // Best score on the winning team
+	performanceBadgeACE = "ACE" // Best score on the losing team
+)
+
+// PerformanceScoreWeights sets how much each metric contributes to the performance score.
+// Weights are relative to each other; they don't need to sum to 1.
+type PerformanceScoreWeights struct {
+	KDA               float64
+	KillParticipation float64
+	DamageShare       float64
+	GoldShare         float64
+	Vision            float64
+	Objectives        float64
+	CS                float64
+}
+
+var defaultPerformanceScoreWeights = PerformanceScoreWeights{
+	KDA:               0.25,
+	KillParticipation: 0.20,
+	DamageShare:       0.20,
+	GoldShare:         0.10,
+	Vision:            0.10,
+	Objectives:        0.10,
+	CS:                0.05,
+}
+
+// performanceGrades maps minimum scores to letter grades, best first
+var performanceGrades = []struct {
+	minScore float64
+	grade    string
+}{
+	{8.5, "S+"},
+	{7.5, "S"},
+	{6.5, "A"},
+	{5.5, "B"},
+	{4.5, "C"},
+	{0, "D"},
+}
+
+var (
+	performanceScoreWeightsOnce sync.Once
+	performanceScoreWeights     PerformanceScoreWeights
+)
+
+// getPerformanceScoreWeights returns the weights from PERFORMANCE_SCORE_WEIGHTS, read once
+// since every extracted match is scored with them
+func getPerformanceScoreWeights() PerformanceScoreWeights {
+	performanceScoreWeightsOnce.Do(func() {
+		performanceScoreWeights = parsePerformanceScoreWeights(os.Getenv("PERFORMANCE_SCORE_WEIGHTS"))
+	})
+	return performanceScoreWeights
+}
+
+// parsePerformanceScoreWeights returns the default weights overridden by weightsStr, in the form
+// "kda=0.3,kp=0.2,damage=0.2,gold=0.1,vision=0.1,objectives=0.05,cs=0.05".
+// Metrics left out keep their default weight.
+func parsePerformanceScoreWeights(weightsStr string) PerformanceScoreWeights {
+	weights := defaultPerformanceScoreWeights
+	if weightsStr == "" {
+		return weights
+	}
+
+	for _, pair := range strings.Split(weightsStr, ",") {
+		name, valueStr, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		value, err := strconv.ParseFloat(strings.TrimSpace(valueStr), 64)
+		if !ok || err != nil || value < 0 {
+			log.Printf("Ignoring invalid PERFORMANCE_SCORE_WEIGHTS entry %q", pair)
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "kda":
+			weights.KDA = value
+		case "kp":
+			weights.KillParticipation = value
+		case "damage":
+			weights.DamageShare = value
+		case "gold":
+			weights.GoldShare = value
+		case "vision":
+			weights.Vision = value
+		case "objectives":
+			weights.Objectives = value
+		case "cs":
+			weights.CS = value
+		default:
+			log.Printf("Ignoring unknown PERFORMANCE_SCORE_WEIGHTS metric %q", name)
+		}
+	}
+	return weights
+}
+
+// ParticipantScore is one participant's performance score within a match
+type ParticipantScore struct {
+	Score float64
+	Grade string
+	Badge string // performanceBadgeMVP, performanceBadgeACE or empty
+}
+
+func performanceGrade(score float64) string {
+	for _, g := range performanceGrades {
+		if score >= g.minScore {
+			return g.grade
+		}
+	}
+	return performanceGrades[len(performanceGrades)-1].grade
+}
+
+// scoreMatchParticipants scores every participant from 0 to 10. Each metric is normalized
+// against the best value in the lobby, so a score reflects performance relative to the
+// other participants. The result depends only on the match and the weights: participants
+// are visited in match order and ties for MVP/ACE go to the earlier participant. Nobody gets
+// a badge for a score of 0, e.g. in a remake where nothing happened.
+func scoreMatchParticipants(info *MatchInfoDto, weights PerformanceScoreWeights) map[string]ParticipantScore {
+	participants := info.Participants
+	if len(participants) == 0 {
+		return map[string]ParticipantScore{}
+	}
+
+	type teamTotals struct {
+		kills, damage, gold int
+	}
+	teams := map[int]*teamTotals{}
+	for _, p := range participants {
+		t, ok := teams[p.TeamID]
+		if !ok {
+			t = &teamTotals{}
+			teams[p.TeamID] = t
+		}
+		t.kills += p.Kills
+		t.damage += p.TotalDamageDealtToChampions
+		t.gold += p.GoldEarned
+	}
+
+	minutes := float64(info.GameDuration) / 60
+	if minutes <= 0 {
+		minutes = 1
+	}
+	share := func(value, total int) float64 {
+		if total <= 0 {
+			return 0
+		}
+		return float64(value) / float64(total)
+	}
+
+	// Raw metrics in weight order: KDA, KP, damage share, gold share, vision/min, objective damage, CS/min
+	metricWeights := []float64{weights.KDA, weights.KillParticipation, weights.DamageShare, weights.GoldShare, weights.Vision, weights.Objectives, weights.CS}
+	raw := make([][]float64, len(participants))
+	best := make([]float64, len(metricWeights))
+	for i, p := range participants {
+		deaths := p.Deaths
+		if deaths == 0 {
+			deaths = 1
+		}
+		t := teams[p.TeamID]
+		raw[i] = []float64{
+			float64(p.Kills+p.Assists) / float64(deaths),
+			share(p.Kills+p.Assists, t.kills),
+			share(p.TotalDamageDealtToChampions, t.damage),
+			share(p.GoldEarned, t.gold),
+			float64(p.VisionScore) / minutes,
+			float64(p.DamageDealtToObjectives),
+			float64(p.TotalMinionsKilled+p.NeutralMinionsKilled) / minutes,
+		}
+		for m, value := range raw[i] {
+			if value > best[m] {
+				best[m] = value
+			}
+		}
+	}
+
+	totalWeight := 0.0
+	for _, w := range metricWeights {
+		totalWeight += w
+	}
+
+	scores := make(map[string]ParticipantScore, len(participants))
+	mvpIndex, aceIndex := -1, -1
+	values := make([]float64, len(participants))
+	for i, p := range participants {
+		score := 0.0
+		if totalWeight > 0 {
+			for m, value := range raw[i] {
+				if best[m] > 0 {
+					score += metricWeights[m] * value / best[m]
+				}
+			}
+			score = score / totalWeight * maxPerformanceScore
+		}
+		values[i] = score
+
+		if score <= 0 {
+			continue
+		}
+		if p.Win {
+			if mvpIndex < 0 || score > values[mvpIndex] {
+				mvpIndex = i
+			}
+		} else if aceIndex < 0 || score > values[aceIndex] {
+			aceIndex = i
+		}
+	}
+
+	for i, p := range participants {
+		result := ParticipantScore{Score: values[i], Grade: performanceGrade(values[i])}
+		switch i {
+		case mvpIndex:
+			result.Badge = performanceBadgeMVP
+		case aceIndex:
+			result.Badge = performanceBadgeACE
+		}
+		scores[p.PUUID] = result
+	}
+	return scores
+}
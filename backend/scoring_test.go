@@ -0,0 +1,186 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func scoringParticipant(puuid string, teamID int, win bool, kills, deaths, assists, damage, gold, vision, objectives, cs int) ParticipantDto {
+	return ParticipantDto{
+		PUUID:                       puuid,
+		TeamID:                      teamID,
+		Win:                         win,
+		Kills:                       kills,
+		Deaths:                      deaths,
+		Assists:                     assists,
+		TotalDamageDealtToChampions: damage,
+		GoldEarned:                  gold,
+		VisionScore:                 vision,
+		DamageDealtToObjectives:     objectives,
+		TotalMinionsKilled:          cs,
+	}
+}
+
+func TestScoreMatchParticipants(t *testing.T) {
+	// Best in every metric of the lobby
+	winner := scoringParticipant("winner", 100, true, 5, 1, 0, 1000, 1000, 10, 100, 100)
+	// Only the whole of their team's gold, which is worth the gold share weight
+	loser := scoringParticipant("loser", 200, false, 0, 5, 0, 0, 500, 0, 0, 0)
+	even := func(puuid string, teamID int, win bool) ParticipantDto {
+		return scoringParticipant(puuid, teamID, win, 2, 2, 2, 500, 500, 5, 50, 50)
+	}
+	idle := func(puuid string, teamID int, win bool) ParticipantDto {
+		return scoringParticipant(puuid, teamID, win, 0, 0, 0, 0, 0, 0, 0, 0)
+	}
+
+	type expected struct {
+		score float64
+		grade string
+		badge string
+	}
+	tests := []struct {
+		name         string
+		duration     int64
+		weights      PerformanceScoreWeights
+		participants []ParticipantDto
+		want         map[string]expected
+	}{
+		{
+			name:         "best and worst",
+			duration:     1800,
+			weights:      defaultPerformanceScoreWeights,
+			participants: []ParticipantDto{winner, loser},
+			want: map[string]expected{
+				"winner": {score: 10, grade: "S+", badge: performanceBadgeMVP},
+				"loser":  {score: 1, grade: "D", badge: performanceBadgeACE},
+			},
+		},
+		{
+			name:         "ties go to the earlier participant",
+			duration:     1800,
+			weights:      defaultPerformanceScoreWeights,
+			participants: []ParticipantDto{even("w1", 100, true), even("w2", 100, true), even("l1", 200, false), even("l2", 200, false)},
+			want: map[string]expected{
+				"w1": {score: 10, grade: "S+", badge: performanceBadgeMVP},
+				"w2": {score: 10, grade: "S+"},
+				"l1": {score: 10, grade: "S+", badge: performanceBadgeACE},
+				"l2": {score: 10, grade: "S+"},
+			},
+		},
+		{
+			name:         "zero duration",
+			duration:     0,
+			weights:      defaultPerformanceScoreWeights,
+			participants: []ParticipantDto{winner, loser},
+			want: map[string]expected{
+				"winner": {score: 10, grade: "S+", badge: performanceBadgeMVP},
+				"loser":  {score: 1, grade: "D", badge: performanceBadgeACE},
+			},
+		},
+		{
+			name:         "remake",
+			duration:     180,
+			weights:      defaultPerformanceScoreWeights,
+			participants: []ParticipantDto{idle("w1", 100, true), idle("l1", 200, false)},
+			want: map[string]expected{
+				"w1": {score: 0, grade: "D"},
+				"l1": {score: 0, grade: "D"},
+			},
+		},
+		{
+			name:         "custom weights",
+			duration:     1800,
+			weights:      PerformanceScoreWeights{KDA: 1},
+			participants: []ParticipantDto{winner, loser},
+			want: map[string]expected{
+				"winner": {score: 10, grade: "S+", badge: performanceBadgeMVP},
+				"loser":  {score: 0, grade: "D"},
+			},
+		},
+		{
+			name:         "zero weights",
+			duration:     1800,
+			weights:      PerformanceScoreWeights{},
+			participants: []ParticipantDto{winner, loser},
+			want: map[string]expected{
+				"winner": {score: 0, grade: "D"},
+				"loser":  {score: 0, grade: "D"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := &MatchInfoDto{GameDuration: tt.duration, Participants: tt.participants}
+			scores := scoreMatchParticipants(info, tt.weights)
+			if len(scores) != len(tt.want) {
+				t.Fatalf("got %d scores, want %d", len(scores), len(tt.want))
+			}
+			for puuid, want := range tt.want {
+				got, ok := scores[puuid]
+				if !ok {
+					t.Fatalf("no score for %s", puuid)
+				}
+				if math.Abs(got.Score-want.score) > 1e-9 {
+					t.Errorf("%s: score = %v, want %v", puuid, got.Score, want.score)
+				}
+				if got.Grade != want.grade {
+					t.Errorf("%s: grade = %q, want %q", puuid, got.Grade, want.grade)
+				}
+				if got.Badge != want.badge {
+					t.Errorf("%s: badge = %q, want %q", puuid, got.Badge, want.badge)
+				}
+			}
+		})
+	}
+}
+
+func TestPerformanceGrade(t *testing.T) {
+	tests := []struct {
+		score float64
+		want  string
+	}{
+		{10, "S+"},
+		{8.5, "S+"},
+		{8.49, "S"},
+		{7.5, "S"},
+		{6.5, "A"},
+		{5.5, "B"},
+		{4.5, "C"},
+		{4.49, "D"},
+		{0, "D"},
+		{-1, "D"},
+	}
+	for _, tt := range tests {
+		if got := performanceGrade(tt.score); got != tt.want {
+			t.Errorf("performanceGrade(%v) = %q, want %q", tt.score, got, tt.want)
+		}
+	}
+}
+
+func TestParsePerformanceScoreWeights(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  PerformanceScoreWeights
+	}{
+		{name: "empty", input: "", want: defaultPerformanceScoreWeights},
+		{
+			name:  "partial override",
+			input: "kda=0.5, cs=0",
+			want: func() PerformanceScoreWeights {
+				w := defaultPerformanceScoreWeights
+				w.KDA, w.CS = 0.5, 0
+				return w
+			}(),
+		},
+		{name: "invalid and unknown entries", input: "kda=abc,kda=-1,lp=2,vision", want: defaultPerformanceScoreWeights},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parsePerformanceScoreWeights(tt.input); got != tt.want {
+				t.Errorf("parsePerformanceScoreWeights(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
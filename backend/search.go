@@ -0,0 +1,294 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	searchIndexCollection   = "searchindex"
+	defaultSearchLimit      = 10
+	maxSearchLimit          = 25
+	searchCacheTTL          = 5 * time.Minute
+	searchCacheKeyPrefix    = "search:"
+	searchRankExact         = 0
+	searchRankRiotIDPrefix  = 1
+	searchRankWordPrefix    = 2
+	searchRankNotSuggestion = 3
+)
+
+// foldSearchText lowercases s and strips its accents so "Fåker", "FAKER" and fullwidth
+// "Ｆａｋｅｒ" index alike. Runs of whitespace collapse to one space.
+func foldSearchText(s string) string {
+	// Transformers keep state, so each call builds its own
+	stripAccents := transform.Chain(norm.NFKD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(stripAccents, s)
+	if err != nil {
+		folded = s
+	}
+	return strings.Join(strings.Fields(cases.Fold().String(folded)), " ")
+}
+
+// searchKey is the folded form of a Riot ID, or of a query for one, that prefixes are matched
+// against: "faker#kr1", or just "fak" for a query without a tagline
+func searchKey(gameName, tagLine string) string {
+	key := foldSearchText(gameName)
+	if tagLine != "" {
+		key += "#" + foldSearchText(tagLine)
+	}
+	return key
+}
+
+// searchQueryKey folds a search query, ignoring spaces around the #
+func searchQueryKey(query string) string {
+	gameName, tagLine, hasTag := strings.Cut(query, "#")
+	if !hasTag {
+		return foldSearchText(gameName)
+	}
+	return foldSearchText(gameName) + "#" + foldSearchText(tagLine)
+}
+
+// searchWords returns the folded game name from each of its later words on, so "bush" and
+// "on b" both find "Hide on Bush"
+func searchWords(gameName string) []string {
+	fields := strings.Fields(foldSearchText(gameName))
+	words := make([]string, 0, len(fields))
+	for i := 1; i < len(fields); i++ {
+		words = append(words, strings.Join(fields[i:], " "))
+	}
+	return words
+}
+
+// toSearchEntry returns the search index entry for a participant seen in a match in region
+func toSearchEntry(region string, seenAt int64, p *ParticipantDto) *SearchEntry {
+	if p.PUUID == "" || p.RiotIDGameName == "" || p.RiotIDTagline == "" {
+		return nil
+	}
+	return &SearchEntry{
+		ID:        region + ":" + p.PUUID,
+		PUUID:     p.PUUID,
+		Region:    region,
+		GameName:  p.RiotIDGameName,
+		TagLine:   p.RiotIDTagline,
+		SearchKey: searchKey(p.RiotIDGameName, p.RiotIDTagline),
+		Words:     searchWords(p.RiotIDGameName),
+		LastSeen:  seenAt,
+	}
+}
+
+// indexSearchEntries adds every participant of a freshly ingested match to the search index.
+// An entry keeps the Riot ID from the newest match, so old matches never bring back a
+// pre-rename name.
+func indexSearchEntries(app *GlobalAppData, region string, match *MatchDto) {
+	if match == nil || region == "" {
+		return
+	}
+	seenAt := match.Info.GameCreation / 1000
+
+	created := make([]mongo.WriteModel, 0, len(match.Info.Participants))
+	updated := make([]mongo.WriteModel, 0, len(match.Info.Participants))
+	for i := range match.Info.Participants {
+		entry := toSearchEntry(region, seenAt, &match.Info.Participants[i])
+		if entry == nil || PreventNoSQLInjection(entry.ID) != nil {
+			continue
+		}
+		// Create the entry for a player seen for the first time and move lastSeen forward
+		created = append(created, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": entry.ID}).
+			SetUpdate(bson.M{
+				"$setOnInsert": bson.M{"puuid": entry.PUUID, "region": entry.Region},
+				"$max":         bson.M{"lastSeen": entry.LastSeen},
+			}).
+			SetUpsert(true))
+		// Then take the Riot ID if this match is the newest the player was seen in
+		updated = append(updated, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": entry.ID, "lastSeen": entry.LastSeen}).
+			SetUpdate(bson.M{"$set": bson.M{
+				"gameName":  entry.GameName,
+				"tagLine":   entry.TagLine,
+				"searchKey": entry.SearchKey,
+				"words":     entry.Words,
+			}}))
+	}
+	if len(created) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection := app.mongoClient.Database(app.mongoDatabase).Collection(searchIndexCollection)
+	opts := options.BulkWrite().SetOrdered(false)
+	// Concurrent ingests of the same new player can both try to insert it; one insert is enough
+	if _, err := collection.BulkWrite(ctx, created, opts); err != nil && !onlyDuplicateKeyErrors(err) {
+		log.Printf("Search: Error indexing players of match %s: %v", match.Metadata.MatchID, err)
+		return
+	}
+	if _, err := collection.BulkWrite(ctx, updated, opts); err != nil {
+		log.Printf("Search: Error updating Riot IDs of match %s: %v", match.Metadata.MatchID, err)
+	}
+}
+
+// searchRank orders suggestions for a folded query: an exact Riot ID or game name first, then
+// Riot IDs starting with the query, then game names continuing it from a later word
+func searchRank(entry *SearchEntry, queryKey string) int {
+	gameNameKey, _, _ := strings.Cut(entry.SearchKey, "#")
+	switch {
+	case entry.SearchKey == queryKey || gameNameKey == queryKey:
+		return searchRankExact
+	case strings.HasPrefix(entry.SearchKey, queryKey):
+		return searchRankRiotIDPrefix
+	}
+	for _, word := range entry.Words {
+		if strings.HasPrefix(word, queryKey) {
+			return searchRankWordPrefix
+		}
+	}
+	return searchRankNotSuggestion
+}
+
+// searchRiotIDs returns up to limit known Riot IDs matching the folded query, best first.
+// region narrows the search to one platform when set.
+func searchRiotIDs(ctx context.Context, app *GlobalAppData, queryKey, region string, limit int) ([]SearchSuggestion, error) {
+	prefix := "^" + regexp.QuoteMeta(queryKey)
+	// One query per rank, best first, so a common prefix can't crowd out a better match
+	rankFilters := []struct {
+		rank   int
+		filter bson.M
+	}{
+		{searchRankExact, bson.M{"$or": []bson.M{
+			{"searchKey": queryKey},
+			{"searchKey": bson.M{"$regex": prefix + "#"}},
+		}}},
+		{searchRankRiotIDPrefix, bson.M{"searchKey": bson.M{"$regex": prefix}}},
+		{searchRankWordPrefix, bson.M{"words": bson.M{"$regex": prefix}}},
+	}
+
+	collection := app.mongoClient.Database(app.mongoDatabase).Collection(searchIndexCollection)
+	// Entries a query shares with a better rank were all taken there, so limit entries per query
+	// always leave enough to fill the remaining slots
+	opts := options.Find().SetSort(bson.D{{Key: "lastSeen", Value: -1}}).SetLimit(int64(limit))
+	suggestions := make([]SearchSuggestion, 0, limit)
+	for _, rf := range rankFilters {
+		if len(suggestions) == limit {
+			break
+		}
+		if region != "" {
+			rf.filter["region"] = region
+		}
+		cursor, err := collection.Find(ctx, rf.filter, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query search index: %w", err)
+		}
+		var entries []SearchEntry
+		if err := cursor.All(ctx, &entries); err != nil {
+			return nil, fmt.Errorf("failed to decode search index: %w", err)
+		}
+
+		// Entries arrive most recently seen first; those of a better rank are already in
+		for _, entry := range entries {
+			if len(suggestions) == limit {
+				break
+			}
+			if searchRank(&entry, queryKey) != rf.rank {
+				continue
+			}
+			suggestions = append(suggestions, SearchSuggestion{
+				GameName: entry.GameName,
+				TagLine:  entry.TagLine,
+				RiotID:   entry.GameName + "#" + entry.TagLine,
+				Region:   entry.Region,
+				LastSeen: entry.LastSeen,
+			})
+		}
+	}
+	return suggestions, nil
+}
+
+// searchHandler suggests known Riot IDs for a partly typed one, e.g. /api/search?q=fak
+func searchHandler(app *GlobalAppData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query, err := ValidateSearchQuery(r.URL.Query().Get("q"))
+		if err != nil {
+			writeValidationError(w, r, err, "Invalid q parameter")
+			return
+		}
+		if err := PreventNoSQLInjection(query); err != nil {
+			log.Printf("Potential NoSQL injection attempt in search query: %s", query)
+			writeAPIError(w, r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid input detected"))
+			return
+		}
+
+		region := SanitizeString(r.URL.Query().Get("region"))
+		if region != "" {
+			if err := ValidateRegion(region); err != nil {
+				writeValidationError(w, r, err, "Invalid region parameter")
+				return
+			}
+			region = strings.ToLower(region)
+		}
+
+		limit, err := ValidateCount(r.URL.Query().Get("limit"), defaultSearchLimit, maxSearchLimit)
+		if err != nil {
+			writeValidationError(w, r, err, "Invalid limit parameter")
+			return
+		}
+
+		response := SearchResponse{Query: query, Suggestions: []SearchSuggestion{}}
+		queryKey := searchQueryKey(query)
+		if queryKey == "" || queryKey == "#" {
+			writeSearchResponse(w, r, response)
+			return
+		}
+
+		cacheKey := fmt.Sprintf("%s%s:%d:%s", searchCacheKeyPrefix, region, limit, queryKey)
+		if cached, err := app.redisClient.Get(r.Context(), cacheKey).Result(); err == nil {
+			if err := json.Unmarshal([]byte(cached), &response.Suggestions); err == nil {
+				writeSearchResponse(w, r, response)
+				return
+			}
+		}
+
+		suggestions, err := searchRiotIDs(r.Context(), app, queryKey, region, limit)
+		if err != nil {
+			log.Printf("Error searching Riot IDs for %q: %v", query, err)
+			writeError(w, r, err, "Error searching players")
+			return
+		}
+		response.Suggestions = suggestions
+
+		// Move Redis caching off the critical path - run asynchronously
+		go func(key string, suggestions []SearchSuggestion) {
+			cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if suggestionsJSON, err := json.Marshal(suggestions); err == nil {
+				_ = app.redisClient.Set(cacheCtx, key, suggestionsJSON, searchCacheTTL).Err()
+			}
+		}(cacheKey, suggestions)
+
+		writeSearchResponse(w, r, response)
+	}
+}
+
+func writeSearchResponse(w http.ResponseWriter, r *http.Request, response SearchResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", cacheControlSearch)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding search response for %q: %v", response.Query, err)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	// A break longer than this between one game ending and the next starting starts a new session
+	sessionGapThreshold = 30 * time.Minute
+)
+
+// sessionAccumulator sums up results for a SessionPerformance
+type sessionAccumulator struct {
+	games, wins            int
+	kills, deaths, assists int
+	score                  float64
+	scoredGames            int
+}
+
+func (a *sessionAccumulator) add(match PlayerMatchStats) {
+	a.games++
+	if match.Win {
+		a.wins++
+	}
+	a.kills += match.Kills
+	a.deaths += match.Deaths
+	a.assists += match.Assists
+	if match.PerformanceGrade != "" {
+		a.score += match.PerformanceScore
+		a.scoredGames++
+	}
+}
+
+func (a *sessionAccumulator) performance(gameNumber int) SessionPerformance {
+	perf := SessionPerformance{GameNumber: gameNumber, Games: a.games, Wins: a.wins}
+	if a.games == 0 {
+		return perf
+	}
+	perf.WinRate = float64(a.wins) / float64(a.games) * 100
+	if a.deaths > 0 {
+		perf.AvgKDA = float64(a.kills+a.assists) / float64(a.deaths)
+	} else {
+		perf.AvgKDA = float64(a.kills + a.assists)
+	}
+	if a.scoredGames > 0 {
+		perf.AvgPerformanceScore = a.score / float64(a.scoredGames)
+	}
+	return perf
+}
+
+// longestStreaks returns the longest win and loss streaks in matches sorted oldest first
+func longestStreaks(matches []PlayerMatchStats) (longestWin, longestLoss int) {
+	run := 0
+	for i, match := range matches {
+		if i > 0 && match.Win == matches[i-1].Win {
+			run++
+		} else {
+			run = 1
+		}
+		if match.Win && run > longestWin {
+			longestWin = run
+		}
+		if !match.Win && run > longestLoss {
+			longestLoss = run
+		}
+	}
+	return longestWin, longestLoss
+}
+
+// analyzeSessions groups matches into play sessions by the gap between one game ending and
+// the next starting, then computes streaks and per-session-position performance
+func analyzeSessions(matches []PlayerMatchStats) *SessionAnalysis {
+	analysis := &SessionAnalysis{
+		SessionGapMinutes: int(sessionGapThreshold / time.Minute),
+		Sessions:          []PlaySession{},
+		ByGameNumber:      []SessionPerformance{},
+	}
+	if len(matches) == 0 {
+		return analysis
+	}
+
+	ordered := make([]PlayerMatchStats, len(matches))
+	copy(ordered, matches)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].GameCreation < ordered[j].GameCreation
+	})
+
+	gapMs := sessionGapThreshold.Milliseconds()
+	var sessions [][]PlayerMatchStats
+	var lastEnd int64
+	for i, match := range ordered {
+		if i == 0 || match.GameCreation-lastEnd > gapMs {
+			sessions = append(sessions, nil)
+		}
+		sessions[len(sessions)-1] = append(sessions[len(sessions)-1], match)
+		lastEnd = match.GameCreation + match.GameDuration*1000
+	}
+
+	var byGameNumber []*sessionAccumulator
+	var afterWin, afterLoss sessionAccumulator
+	for _, session := range sessions {
+		var total sessionAccumulator
+		matchIDs := make([]string, 0, len(session))
+		for i, match := range session {
+			total.add(match)
+			matchIDs = append(matchIDs, match.MatchID)
+
+			if i >= len(byGameNumber) {
+				byGameNumber = append(byGameNumber, &sessionAccumulator{})
+			}
+			byGameNumber[i].add(match)
+
+			if i > 0 {
+				if session[i-1].Win {
+					afterWin.add(match)
+				} else {
+					afterLoss.add(match)
+				}
+			}
+		}
+
+		last := session[len(session)-1]
+		longestWin, longestLoss := longestStreaks(session)
+		analysis.Sessions = append(analysis.Sessions, PlaySession{
+			StartTime:         session[0].GameCreation,
+			EndTime:           last.GameCreation + last.GameDuration*1000,
+			Performance:       total.performance(0),
+			LongestWinStreak:  longestWin,
+			LongestLossStreak: longestLoss,
+			MatchIDs:          matchIDs,
+		})
+	}
+
+	// Newest session first, like the match list
+	for i, j := 0, len(analysis.Sessions)-1; i < j; i, j = i+1, j-1 {
+		analysis.Sessions[i], analysis.Sessions[j] = analysis.Sessions[j], analysis.Sessions[i]
+	}
+
+	for i, acc := range byGameNumber {
+		analysis.ByGameNumber = append(analysis.ByGameNumber, acc.performance(i+1))
+	}
+	analysis.AfterWin = afterWin.performance(0)
+	analysis.AfterLoss = afterLoss.performance(0)
+	analysis.LongestWinStreak, analysis.LongestLossStreak = longestStreaks(ordered)
+
+	// The current streak runs back from the most recent game
+	latest := ordered[len(ordered)-1]
+	analysis.CurrentStreak.Type = "loss"
+	if latest.Win {
+		analysis.CurrentStreak.Type = "win"
+	}
+	for i := len(ordered) - 1; i >= 0 && ordered[i].Win == latest.Win; i-- {
+		analysis.CurrentStreak.Length++
+	}
+
+	return analysis
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+const flashSpellKey = "SummonerFlash"
+
+// flashSpellID looks up Flash's numeric spell ID in the loaded summoner spell data
+func flashSpellID(sd *StaticData) int {
+	if sd == nil {
+		return 0
+	}
+	for key, spell := range sd.SummonerSpells {
+		if spell.ID == flashSpellKey {
+			id, _ := strconv.Atoi(key)
+			return id
+		}
+	}
+	return 0
+}
+
+// calculateSummonerSpellStats aggregates spell picks, casts and spell pairs per champion and
+// role, and detects which key (D or F) the player keeps Flash on
+func calculateSummonerSpellStats(sd *StaticData, matches []PlayerMatchStats) *SummonerSpellSummary {
+	type usageAgg struct {
+		games, onD, onF    int
+		casts, castedGames int
+	}
+	type pairAgg struct {
+		championName string
+		championID   int
+		role         string
+		spells       [2]int
+		games, wins  int
+	}
+
+	usage := map[int]*usageAgg{}
+	pairs := map[string]*pairAgg{}
+
+	for _, match := range matches {
+		if len(match.SummonerSpells) != 2 {
+			continue
+		}
+
+		for slot, spellID := range match.SummonerSpells {
+			if spellID == 0 {
+				continue
+			}
+			u, ok := usage[spellID]
+			if !ok {
+				u = &usageAgg{}
+				usage[spellID] = u
+			}
+			u.games++
+			if slot == 0 {
+				u.onD++
+			} else {
+				u.onF++
+			}
+			// Matches stored before cast counts were recorded don't count towards the average
+			if len(match.SummonerSpellCasts) == 2 {
+				u.casts += match.SummonerSpellCasts[slot]
+				u.castedGames++
+			}
+		}
+
+		spells := [2]int{match.SummonerSpells[0], match.SummonerSpells[1]}
+		if spells[0] > spells[1] {
+			spells[0], spells[1] = spells[1], spells[0]
+		}
+		role := normalizeRole(match.TeamPosition, match.GameMode)
+		key := fmt.Sprintf("%d|%s|%d|%d", match.ChampionID, role, spells[0], spells[1])
+		p, ok := pairs[key]
+		if !ok {
+			p = &pairAgg{championName: match.ChampionName, championID: match.ChampionID, role: role, spells: spells}
+			pairs[key] = p
+		}
+		p.games++
+		if match.Win {
+			p.wins++
+		}
+	}
+
+	summary := &SummonerSpellSummary{
+		Spells: make([]SummonerSpellUsage, 0, len(usage)),
+		Pairs:  make([]SummonerSpellPairStats, 0, len(pairs)),
+	}
+
+	// Flash goes on whichever key it was taken on more often
+	if flash, ok := usage[flashSpellID(sd)]; ok {
+		if flash.onD >= flash.onF {
+			summary.FlashKey = "D"
+		} else {
+			summary.FlashKey = "F"
+		}
+	}
+
+	for spellID, u := range usage {
+		entry := SummonerSpellUsage{
+			Spell:      resolveSummonerSpellRef(sd, spellID),
+			Games:      u.games,
+			GamesOnD:   u.onD,
+			GamesOnF:   u.onF,
+			TotalCasts: u.casts,
+		}
+		if u.castedGames > 0 {
+			entry.AvgCasts = float64(u.casts) / float64(u.castedGames)
+		}
+		summary.Spells = append(summary.Spells, entry)
+	}
+	sort.Slice(summary.Spells, func(i, j int) bool {
+		if summary.Spells[i].Games != summary.Spells[j].Games {
+			return summary.Spells[i].Games > summary.Spells[j].Games
+		}
+		return summary.Spells[i].Spell.ID < summary.Spells[j].Spell.ID
+	})
+
+	for _, p := range pairs {
+		summary.Pairs = append(summary.Pairs, SummonerSpellPairStats{
+			ChampionName: p.championName,
+			ChampionID:   p.championID,
+			Role:         p.role,
+			Spells:       []StaticRef{resolveSummonerSpellRef(sd, p.spells[0]), resolveSummonerSpellRef(sd, p.spells[1])},
+			Games:        p.games,
+			Wins:         p.wins,
+			WinRate:      float64(p.wins) / float64(p.games) * 100,
+		})
+	}
+	sort.Slice(summary.Pairs, func(i, j int) bool {
+		a, b := summary.Pairs[i], summary.Pairs[j]
+		if a.Games != b.Games {
+			return a.Games > b.Games
+		}
+		if a.ChampionName != b.ChampionName {
+			return a.ChampionName < b.ChampionName
+		}
+		if a.Role != b.Role {
+			return a.Role < b.Role
+		}
+		if a.Spells[0].ID != b.Spells[0].ID {
+			return a.Spells[0].ID < b.Spells[0].ID
+		}
+		return a.Spells[1].ID < b.Spells[1].ID
+	})
+
+	return summary
+}
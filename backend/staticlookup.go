@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Helpers that resolve IDs found in match data to names and image URLs from StaticData.
+// Unknown IDs still resolve to a StaticRef carrying the ID so callers never have to nil-check.
+
+func dataDragonImageURL(version, group, file string) string {
+	if file == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/cdn/%s/img/%s/%s", dataDragonBaseURL, version, group, file)
+}
+
+func resolveChampionRef(sd *StaticData, championID int) StaticRef {
+	ref := StaticRef{ID: championID}
+	if sd == nil {
+		return ref
+	}
+	if champ, ok := sd.Champions[strconv.Itoa(championID)]; ok {
+		ref.Name = champ.Name
+		ref.Image = dataDragonImageURL(sd.LatestVersion, "champion", champ.Image.Full)
+	}
+	return ref
+}
+
+func resolveItemRef(sd *StaticData, itemID int) StaticRef {
+	ref := StaticRef{ID: itemID}
+	if sd == nil || itemID == 0 {
+		return ref
+	}
+	if item, ok := sd.Items[strconv.Itoa(itemID)]; ok {
+		ref.Name = item.Name
+		ref.Image = dataDragonImageURL(sd.LatestVersion, "item", item.Image.Full)
+	}
+	return ref
+}
+
+func resolveSummonerSpellRef(sd *StaticData, spellID int) StaticRef {
+	ref := StaticRef{ID: spellID}
+	if sd == nil {
+		return ref
+	}
+	if spell, ok := sd.SummonerSpells[strconv.Itoa(spellID)]; ok {
+		ref.Name = spell.Name
+		ref.Image = dataDragonImageURL(sd.LatestVersion, "spell", spell.Image.Full)
+	}
+	return ref
+}
+
+func resolveRuneRef(sd *StaticData, runeID int) StaticRef {
+	ref := StaticRef{ID: runeID}
+	if sd == nil {
+		return ref
+	}
+	if runeInfo, ok := sd.Runes[runeID]; ok {
+		ref.Name = runeInfo.Name
+		if runeInfo.Icon != "" {
+			// Rune icons are not versioned
+			ref.Image = fmt.Sprintf("%s/cdn/img/%s", dataDragonBaseURL, runeInfo.Icon)
+		}
+	}
+	return ref
+}
+
+func resolveAugmentRef(sd *StaticData, augmentID int) StaticRef {
+	ref := StaticRef{ID: augmentID}
+	if sd == nil {
+		return ref
+	}
+	if augment, ok := sd.Augments[augmentID]; ok {
+		ref.Name = augment.Name
+		if augment.IconLarge != "" {
+			// CommunityDragon serves game assets under lowercased paths
+			ref.Image = fmt.Sprintf("%s/game/%s", communityDragonBaseURL, strings.ToLower(augment.IconLarge))
+		}
+	}
+	return ref
+}
+
+func resolveRunePathRef(sd *StaticData, styleID int) StaticRef {
+	ref := StaticRef{ID: styleID}
+	if sd == nil {
+		return ref
+	}
+	if path, ok := sd.RunePaths[styleID]; ok {
+		ref.Name = path.Name
+		if path.Icon != "" {
+			ref.Image = fmt.Sprintf("%s/cdn/img/%s", dataDragonBaseURL, path.Icon)
+		}
+	}
+	return ref
+}
+
+// Stat shards are not part of runesReforged.json, so their names and icons are kept here
+var statShards = map[int]struct{ name, icon string }{
+	5001: {"Health Scaling", "StatModsHealthScalingIcon.png"},
+	5002: {"Armor", "StatModsArmorIcon.png"},
+	5003: {"Magic Resist", "StatModsMagicResIcon.MagicResist_Fix.png"},
+	5005: {"Attack Speed", "StatModsAttackSpeedIcon.png"},
+	5007: {"Ability Haste", "StatModsCDRScalingIcon.png"},
+	5008: {"Adaptive Force", "StatModsAdaptiveForceIcon.png"},
+	5010: {"Move Speed", "StatModsMovementSpeedIcon.png"},
+	5011: {"Health", "StatModsHealthPlusIcon.png"},
+	5013: {"Tenacity and Slow Resist", "StatModsTenacityIcon.png"},
+}
+
+func resolveStatShardRef(shardID int) StaticRef {
+	ref := StaticRef{ID: shardID}
+	if shard, ok := statShards[shardID]; ok {
+		ref.Name = shard.name
+		ref.Image = fmt.Sprintf("%s/cdn/img/perk-images/StatMods/%s", dataDragonBaseURL, shard.icon)
+	}
+	return ref
+}
+
+// resolveChampionFilter accepts a champion key ("266"), ID ("Aatrox") or display name
+// ("Aatrox", "Kai'Sa") and returns its numeric champion ID
+func resolveChampionFilter(sd *StaticData, value string) (int, error) {
+	value = SanitizeString(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	if id, err := strconv.Atoi(value); err == nil && id > 0 {
+		return id, nil
+	}
+
+	if sd != nil {
+		for key, champ := range sd.Champions {
+			if strings.EqualFold(champ.ID, value) || strings.EqualFold(champ.Name, value) {
+				if id, err := strconv.Atoi(key); err == nil {
+					return id, nil
+				}
+			}
+		}
+	}
+
+	return 0, ValidationError{Field: "champion", Message: "unknown champion"}
+}
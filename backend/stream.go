@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// How often a running stream sends an IncrementalStats snapshot
+	streamStatsInterval = 1 * time.Second
+)
+
+// sseWriter writes server-sent events and flushes after each one
+type sseWriter struct {
+	w      http.ResponseWriter
+	rc     *http.ResponseController
+	nextID int
+}
+
+func newSSEWriter(w http.ResponseWriter) *sseWriter {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Stop reverse proxies from buffering the stream
+	w.WriteHeader(http.StatusOK)
+	return &sseWriter{w: w, rc: http.NewResponseController(w)}
+}
+
+// send writes one event with a JSON payload
+func (s *sseWriter) send(event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", event, err)
+	}
+
+	s.nextID++
+	if _, err := fmt.Fprintf(s.w, "id: %d\nevent: %s\ndata: %s\n\n", s.nextID, event, payload); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+// sendError reports a failure to the client with the same body as API error responses; the
+// stream ends afterwards
+func (s *sseWriter) sendError(r *http.Request, apiErr *APIError) {
+	body := ErrorBody{
+		Code:       apiErr.Code,
+		Status:     apiErr.Status,
+		Message:    apiErr.Message,
+		RetryAfter: apiErr.RetryAfter,
+		RequestID:  requestIDFromContext(r.Context()),
+		Details:    apiErr.Details,
+	}
+	if err := s.send("error", body); err != nil {
+		log.Printf("Stream: Failed to send error event: %v", err)
+	}
+}
+
+// cachedFirstPage returns the first count matches of the player's cached first page, by the
+// same rules as the regular dashboard, or nil when the cache can't serve them
+func cachedFirstPage(ctx context.Context, app *GlobalAppData, region, puuid string, count int) *UserPerformance {
+	var cached UserPerformance
+	val, err := app.redisClient.Get(ctx, userPerformanceRedisKey(region, puuid, 0)).Result()
+	if err != nil || json.Unmarshal([]byte(val), &cached) != nil || len(cached.Matches) < count {
+		cached = UserPerformance{}
+		collection := app.mongoClient.Database(app.mongoDatabase).Collection("userperformances")
+		err := collection.FindOne(ctx, bson.M{"_id": puuid, "region": region}).Decode(&cached)
+		if err != nil || len(cached.Matches) < count || time.Now().Unix()-cached.UpdatedAt >= int64(userPerformanceCacheDuration/time.Second)/2 {
+			return nil
+		}
+	}
+	cached.Matches = cached.Matches[:count]
+	return &cached
+}
+
+// streamDashboard emits each match as soon as it is extracted, periodic IncrementalStats
+// snapshots while fetching, and the full RecentGamesSummary once every match is in. A player
+// whose matches are already cached gets them all at once, without a fetch.
+func streamDashboard(w http.ResponseWriter, r *http.Request, app *GlobalAppData, region, gameName, tagLine string, count, queueID int) {
+	// The server WriteTimeout is far shorter than a full fetch, so extend it for this response
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Now().Add(defaultTimeout * time.Duration(count+5))); err != nil {
+		log.Printf("Stream: Could not extend write deadline: %v", err)
+	}
+
+	stream := newSSEWriter(w)
+	ctx := r.Context()
+
+	puuid, err := getPUUID(app, region, gameName, tagLine)
+	if err != nil {
+		log.Printf("Stream: Error getting PUUID for %s#%s: %v", gameName, tagLine, err)
+		stream.sendError(r, toAPIError(err, "Could not find player"))
+		return
+	}
+	if err := PreventNoSQLInjection(puuid); err != nil {
+		log.Printf("Stream: Potential injection attempt in PUUID: %s", puuid)
+		stream.sendError(r, newAPIError(http.StatusBadRequest, ErrCodeInvalidParameter, "Invalid player data"))
+		return
+	}
+
+	riotID := gameName + "#" + tagLine
+
+	// The cache holds unfiltered matches only
+	if queueID == 0 {
+		if cached := cachedFirstPage(ctx, app, region, puuid, count); cached != nil {
+			log.Printf("Stream: Serving %d cached matches for %s", len(cached.Matches), riotID)
+			refreshRiotID(app, cached, gameName, tagLine)
+			if err := stream.send("meta", DashboardStreamMeta{PUUID: puuid, Region: region, RiotID: riotID, TotalMatches: len(cached.Matches)}); err != nil {
+				return
+			}
+			for _, stats := range cached.Matches {
+				if err := stream.send("match", stats); err != nil {
+					return
+				}
+			}
+			finishStream(ctx, stream, app, cached.Matches, puuid, region, riotID, cached.RankTier)
+			return
+		}
+	}
+
+	matchIDs, err := getMatchIDs(app, region, puuid, count, queueID, 0, 0)
+	if err != nil {
+		log.Printf("Stream: Error getting match IDs for %s: %v", puuid, err)
+		stream.sendError(r, toAPIError(err, "Could not load match history"))
+		return
+	}
+
+	if err := stream.send("meta", DashboardStreamMeta{PUUID: puuid, Region: region, RiotID: riotID, TotalMatches: len(matchIDs)}); err != nil {
+		return
+	}
+
+	// Buffered so the fetchers never block on a slow or departed client
+	matchChan := make(chan PlayerMatchStats, len(matchIDs))
+	resultChan := make(chan []PlayerMatchStats, 1)
+	go func() {
+		matches := fetchMatchesConcurrentlyWithProgress(app, region, matchIDs, puuid, func(stats *PlayerMatchStats) {
+			if stats != nil {
+				matchChan <- *stats
+			}
+		})
+		close(matchChan)
+		resultChan <- matches
+	}()
+
+	ticker := time.NewTicker(streamStatsInterval)
+	defer ticker.Stop()
+
+	var received []PlayerMatchStats
+	snapshotSize := 0
+
+streamLoop:
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("Stream: Client disconnected from %s stream after %d matches", riotID, len(received))
+			return
+		case stats, ok := <-matchChan:
+			if !ok {
+				break streamLoop
+			}
+			received = append(received, stats)
+			if err := stream.send("match", stats); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if len(received) == snapshotSize {
+				continue
+			}
+			snapshotSize = len(received)
+			if err := stream.send("stats", calculateIncrementalStats(received)); err != nil {
+				return
+			}
+		}
+	}
+
+	// Already sorted newest first by the fetcher
+	matches := <-resultChan
+	if matches == nil {
+		matches = []PlayerMatchStats{}
+	}
+
+	// Store exactly like a synchronous first-page fetch so the regular dashboard is warm
+	// afterwards. A queue-filtered fetch would pass for the unfiltered first page, so it isn't stored.
+	tier := rankTierWithBudget(ctx, app, nil, region, puuid)
+	if queueID == 0 {
+		performance := UserPerformance{
+			PUUID:     puuid,
+			Region:    region,
+			RiotID:    riotID,
+			Matches:   matches,
+			RankTier:  tier,
+			UpdatedAt: time.Now().Unix(),
+		}
+		go persistUserPerformance(app, performance, userPerformanceRedisKey(region, puuid, 0))
+	}
+
+	finishStream(ctx, stream, app, matches, puuid, region, riotID, tier)
+}
+
+// finishStream sends the final stats and summary of a stream's matches, then ends it
+func finishStream(ctx context.Context, stream *sseWriter, app *GlobalAppData, matches []PlayerMatchStats, puuid, region, riotID, tier string) {
+	if err := stream.send("stats", calculateIncrementalStats(matches)); err != nil {
+		return
+	}
+	summary := calculateRecentGamesSummary(app.staticData, matches, puuid, region, riotID)
+	annotatePercentiles(ctx, app, summary, tier)
+	if err := stream.send("summary", summary); err != nil {
+		return
+	}
+	_ = stream.send("done", map[string]int{"matchCount": len(matches)})
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	trackedPlayersCollection      = "trackedplayers"
+	defaultTrackerRefreshInterval = 1 * time.Hour
+	defaultTrackerRateShare       = 0.2
+	defaultRiotRateLimitPer2Min   = 100 // Personal/development key limit
+	riotRateLimitWindow           = 2 * time.Minute
+	trackerPollInterval           = 1 * time.Minute
+	trackerRefreshJitter          = 0.1 // +/- 10% of the refresh interval
+	trackerInitialRefreshMaxDelay = 2 * time.Minute
+	trackerDueBatchSize           = 50
+	maxTrackedPlayers             = 500
+	trackerBaseRequestsPerRefresh = 3 // Account lookup + match ID list + rank tier
+)
+
+// riotRateBudget limits how many Riot API calls a background consumer may spend per window
+type riotRateBudget struct {
+	mu       sync.Mutex
+	capacity int
+	window   time.Duration
+	start    time.Time
+	used     int
+}
+
+func newRiotRateBudget(capacity int, window time.Duration) *riotRateBudget {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &riotRateBudget{capacity: capacity, window: window, start: time.Now()}
+}
+
+// reserve blocks until cost calls fit into the current window or ctx is done
+func (b *riotRateBudget) reserve(ctx context.Context, cost int) error {
+	// A single reservation can never exceed the whole window
+	if cost > b.capacity {
+		cost = b.capacity
+	}
+
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		if now.Sub(b.start) >= b.window {
+			b.start = now
+			b.used = 0
+		}
+		if b.used+cost <= b.capacity {
+			b.used += cost
+			b.mu.Unlock()
+			return nil
+		}
+		wait := b.window - now.Sub(b.start)
+		b.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// getRiotRateLimit returns the Riot API request budget per two-minute window
+func getRiotRateLimit() int {
+	if limitStr := os.Getenv("RIOT_RATE_LIMIT_PER_2MIN"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
+			return limit
+		}
+	}
+	return defaultRiotRateLimitPer2Min
+}
+
+// backgroundRiotBudget is the share of the Riot budget spent by background work: tracked player
+// refreshes and the ingested match backfill
+var backgroundRiotBudget = sync.OnceValue(func() *riotRateBudget {
+	return newRiotRateBudget(int(float64(getRiotRateLimit())*getTrackerRateShare()), riotRateLimitWindow)
+})
+
+// getTrackerRateShare returns the fraction of the Riot budget background work may use
+func getTrackerRateShare() float64 {
+	if shareStr := os.Getenv("TRACKER_RATE_SHARE"); shareStr != "" {
+		if share, err := strconv.ParseFloat(shareStr, 64); err == nil && share > 0 && share <= 1 {
+			return share
+		}
+	}
+	return defaultTrackerRateShare
+}
+
+// getTrackerRefreshInterval returns how often each tracked player is refreshed.
+// Intervals shorter than the user performance cache lifetime just re-read the cache.
+func getTrackerRefreshInterval() time.Duration {
+	if intervalStr := os.Getenv("TRACKER_REFRESH_INTERVAL"); intervalStr != "" {
+		if interval, err := time.ParseDuration(intervalStr); err == nil && interval >= time.Minute {
+			return interval
+		}
+	}
+	return defaultTrackerRefreshInterval
+}
+
+// getTrackerMatchCount returns how many matches are refreshed per tracked player
+func getTrackerMatchCount() int {
+	if countStr := os.Getenv("TRACKER_MATCH_COUNT"); countStr != "" {
+		if count, err := strconv.Atoi(countStr); err == nil && count > 0 && count <= 100 {
+			return count
+		}
+	}
+	return defaultMatchCount
+}
+
+// jitterDuration spreads d by +/- fraction so refreshes don't line up
+func jitterDuration(d time.Duration, fraction float64) time.Duration {
+	offset := (rand.Float64()*2 - 1) * fraction * float64(d)
+	return d + time.Duration(offset)
+}
+
+func trackedPlayerID(region, puuid string) string {
+	return fmt.Sprintf("%s_%s", region, puuid)
+}
+
+// addTrackedPlayer resolves the Riot ID and stores it in the tracked players collection.
+// The first refresh is scheduled within a couple of minutes so the player warms up quickly.
+func addTrackedPlayer(ctx context.Context, app *GlobalAppData, region, gameName, tagLine string) (*TrackedPlayer, error) {
+	puuid, err := getPUUID(app, region, gameName, tagLine)
+	if err != nil {
+		return nil, fmt.Errorf("error getting PUUID: %w", err)
+	}
+	if err := PreventNoSQLInjection(puuid); err != nil {
+		return nil, fmt.Errorf("potential injection attempt in PUUID: %w", err)
+	}
+
+	collection := app.mongoClient.Database(app.mongoDatabase).Collection(trackedPlayersCollection)
+	id := trackedPlayerID(region, puuid)
+
+	existing := collection.FindOne(ctx, bson.M{"_id": id})
+	if existing.Err() == mongo.ErrNoDocuments {
+		total, err := collection.CountDocuments(ctx, bson.M{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to count tracked players: %w", err)
+		}
+		if total >= maxTrackedPlayers {
+			return nil, fmt.Errorf("tracked player limit of %d reached", maxTrackedPlayers)
+		}
+	} else if existing.Err() != nil {
+		return nil, fmt.Errorf("failed to look up tracked player: %w", existing.Err())
+	}
+
+	now := time.Now()
+	nextRefresh := now.Add(time.Duration(rand.Int63n(int64(trackerInitialRefreshMaxDelay)))).Unix()
+
+	// Keep addedAt and refresh history on re-adds, but pick up the latest Riot ID spelling
+	update := bson.M{
+		"$set": bson.M{
+			"puuid":         puuid,
+			"region":        region,
+			"gameName":      gameName,
+			"tagLine":       tagLine,
+			"nextRefreshAt": nextRefresh,
+		},
+		"$setOnInsert": bson.M{
+			"addedAt":         now.Unix(),
+			"lastRefreshedAt": int64(0),
+		},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var tracked TrackedPlayer
+	if err := collection.FindOneAndUpdate(ctx, bson.M{"_id": id}, update, opts).Decode(&tracked); err != nil {
+		return nil, fmt.Errorf("failed to store tracked player: %w", err)
+	}
+
+	log.Printf("Tracker: Now tracking %s#%s (%s) in region %s", gameName, tagLine, puuid, region)
+	return &tracked, nil
+}
+
+// removeTrackedPlayer deletes a tracked player, reporting whether it existed
+func removeTrackedPlayer(ctx context.Context, app *GlobalAppData, region, gameName, tagLine string) (bool, error) {
+	puuid, err := getPUUID(app, region, gameName, tagLine)
+	if err != nil {
+		return false, fmt.Errorf("error getting PUUID: %w", err)
+	}
+	if err := PreventNoSQLInjection(puuid); err != nil {
+		return false, fmt.Errorf("potential injection attempt in PUUID: %w", err)
+	}
+
+	collection := app.mongoClient.Database(app.mongoDatabase).Collection(trackedPlayersCollection)
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": trackedPlayerID(region, puuid)})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete tracked player: %w", err)
+	}
+
+	if result.DeletedCount > 0 {
+		log.Printf("Tracker: Stopped tracking %s#%s (%s) in region %s", gameName, tagLine, puuid, region)
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// listTrackedPlayers returns all tracked players, oldest first
+func listTrackedPlayers(ctx context.Context, app *GlobalAppData) ([]TrackedPlayer, error) {
+	collection := app.mongoClient.Database(app.mongoDatabase).Collection(trackedPlayersCollection)
+
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "addedAt", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tracked players: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	players := []TrackedPlayer{}
+	if err := cursor.All(ctx, &players); err != nil {
+		return nil, fmt.Errorf("failed to decode tracked players: %w", err)
+	}
+	return players, nil
+}
+
+// startTrackedPlayerScheduler refreshes due tracked players in the background until ctx is cancelled
+func startTrackedPlayerScheduler(ctx context.Context, app *GlobalAppData) {
+	interval := getTrackerRefreshInterval()
+	budget := backgroundRiotBudget()
+	matchCount := getTrackerMatchCount()
+
+	log.Printf("Tracker: Scheduler started (interval: %v, matches: %d, budget: %d requests per %v)", interval, matchCount, budget.capacity, riotRateLimitWindow)
+
+	go func() {
+		ticker := time.NewTicker(trackerPollInterval)
+		defer ticker.Stop()
+
+		for {
+			refreshDueTrackedPlayers(ctx, app, budget, interval, matchCount)
+
+			select {
+			case <-ctx.Done():
+				log.Println("Tracker: Scheduler stopped.")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// refreshDueTrackedPlayers refreshes every tracked player whose next refresh time has passed
+func refreshDueTrackedPlayers(ctx context.Context, app *GlobalAppData, budget *riotRateBudget, interval time.Duration, matchCount int) {
+	collection := app.mongoClient.Database(app.mongoDatabase).Collection(trackedPlayersCollection)
+
+	queryCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	opts := options.Find().
+		SetSort(bson.D{{Key: "nextRefreshAt", Value: 1}}).
+		SetLimit(trackerDueBatchSize)
+	cursor, err := collection.Find(queryCtx, bson.M{"nextRefreshAt": bson.M{"$lte": time.Now().Unix()}}, opts)
+	if err != nil {
+		cancel()
+		log.Printf("Tracker: Error querying due players: %v", err)
+		return
+	}
+	var due []TrackedPlayer
+	err = cursor.All(queryCtx, &due)
+	cancel()
+	if err != nil {
+		log.Printf("Tracker: Error decoding due players: %v", err)
+		return
+	}
+
+	for _, player := range due {
+		// Worst case every match detail is a cache miss
+		if err := budget.reserve(ctx, matchCount+trackerBaseRequestsPerRefresh); err != nil {
+			return
+		}
+
+		refreshErr := refreshTrackedPlayer(app, player, matchCount)
+
+		now := time.Now()
+		set := bson.M{"nextRefreshAt": now.Add(jitterDuration(interval, trackerRefreshJitter)).Unix()}
+		update := bson.M{"$set": set}
+		if refreshErr != nil {
+			log.Printf("Tracker: Error refreshing %s#%s: %v", player.GameName, player.TagLine, refreshErr)
+			set["lastError"] = refreshErr.Error()
+		} else {
+			set["lastRefreshedAt"] = now.Unix()
+			update["$unset"] = bson.M{"lastError": ""}
+		}
+
+		updateCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if _, err := collection.UpdateOne(updateCtx, bson.M{"_id": player.ID}, update); err != nil {
+			log.Printf("Tracker: Error updating schedule for %s#%s: %v", player.GameName, player.TagLine, err)
+		}
+		cancel()
+	}
+}
+
+// refreshTrackedPlayer pulls the latest matches for a tracked player through the regular fetch path
+func refreshTrackedPlayer(app *GlobalAppData, player TrackedPlayer, matchCount int) error {
+	if app.staticData == nil {
+		if err := populateStaticData(app); err != nil {
+			return fmt.Errorf("static data unavailable: %w", err)
+		}
+	}
+
+	start := time.Now()
+	performance, err := fetchAndStoreUserPerformance(app, player.Region, player.GameName, player.TagLine, matchCount, defaultQueueID, 0)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Tracker: Refreshed %s#%s with %d matches in %v", player.GameName, player.TagLine, len(performance.Matches), time.Since(start))
+	return nil
+}
@@ -2,10 +2,14 @@ package main
 
 import (
 	"fmt"
+	"log"
+	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
+	"unicode/utf8"
 )
 
 // Input validation constants
@@ -15,6 +19,8 @@ const (
 	MaxRegionLength   = 10
 	MaxMatchIDLength  = 50
 	MaxPUUIDLength    = 100
+
+	MaxSearchQueryLength = MaxGameNameLength + 1 + MaxTagLineLength // gameName#tagLine
 )
 
 // Validation error types
@@ -186,8 +192,14 @@ func ValidateCount(countStr string, defaultValue, maxValue int) (int, error) {
 	return count, nil
 }
 
-// ValidateQueueID validates queue ID parameters
-func ValidateQueueID(queueIDStr string, defaultValue int) (int, error) {
+// errQueueCatalogUnavailable is returned for a queue filter that can't be checked because the
+// queue catalog hasn't loaded
+var errQueueCatalogUnavailable = newAPIError(http.StatusServiceUnavailable, ErrCodeUpstreamUnavailable, "Queue data is not available yet. Please try again shortly.")
+
+// ValidateQueueID validates queue ID parameters against the queue catalog in sd. A queue filter
+// is refused with errQueueCatalogUnavailable while the catalog is missing, so callers should load
+// static data first (see validateQueueIDParam).
+func ValidateQueueID(queueIDStr string, defaultValue int, sd *StaticData) (int, error) {
 	if queueIDStr == "" {
 		return defaultValue, nil
 	}
@@ -197,14 +209,46 @@ func ValidateQueueID(queueIDStr string, defaultValue int) (int, error) {
 		return 0, ValidationError{Field: "queueID", Message: "queue ID must be a valid integer"}
 	}
 
-	// Allow any non-negative integer for queue ID
 	if queueID < 0 {
 		return 0, ValidationError{Field: "queueID", Message: "queue ID must be non-negative"}
 	}
 
+	if queueID != defaultValue {
+		if sd == nil || len(sd.Queues) == 0 {
+			return 0, errQueueCatalogUnavailable
+		}
+		if _, ok := sd.Queues[queueID]; !ok {
+			return 0, ValidationError{Field: "queueID", Message: fmt.Sprintf("unknown queue ID %d", queueID)}
+		}
+	}
+
 	return queueID, nil
 }
 
+// validateQueueIDParam loads static data if needed, retrying the queue catalog when it failed to
+// load before, and then validates queueIDStr with ValidateQueueID
+func validateQueueIDParam(app *GlobalAppData, queueIDStr string) (int, error) {
+	if app.staticData == nil {
+		log.Println("Static data not yet loaded, attempting to load now.")
+		if err := populateStaticData(app); err != nil {
+			log.Printf("Error populating static data on demand: %v", err)
+			return 0, newAPIError(http.StatusServiceUnavailable, ErrCodeUpstreamUnavailable, "Error loading required game data. Please try again shortly.")
+		}
+	}
+
+	if len(app.staticData.Queues) == 0 && queueIDStr != "" && queueIDStr != strconv.Itoa(defaultQueueID) {
+		if queues, err := loadQueues(app); err == nil && len(queues) > 0 {
+			updated := *app.staticData
+			updated.Queues = queues
+			app.staticData = &updated
+		} else {
+			log.Printf("Warning: queue catalog still unavailable: %v", err)
+		}
+	}
+
+	return ValidateQueueID(queueIDStr, defaultQueueID, app.staticData)
+}
+
 // SanitizeString removes potentially dangerous characters and normalizes the string
 func SanitizeString(input string) string {
 	// Remove null bytes and other control characters
@@ -327,3 +371,124 @@ func ValidateOffset(offsetStr string, defaultValue int) (int, error) {
 
 	return offset, nil
 }
+
+// jobIDRegex matches the hex job IDs handed out by the fetch job queue
+var jobIDRegex = regexp.MustCompile(`^[a-f0-9]{32}$`)
+
+// ValidateJobID validates a background job ID
+func ValidateJobID(jobID string) error {
+	if !jobIDRegex.MatchString(jobID) {
+		return ValidationError{Field: "jobId", Message: "job ID is malformed"}
+	}
+
+	return nil
+}
+
+// patchRegex matches a "major.minor" patch such as 14.23
+var patchRegex = regexp.MustCompile(`^[0-9]{1,2}\.[0-9]{1,2}$`)
+
+// ValidatePatch validates an optional patch filter
+func ValidatePatch(patch string) (string, error) {
+	patch = SanitizeString(patch)
+	if patch == "" {
+		return "", nil
+	}
+
+	if !patchRegex.MatchString(patch) {
+		return "", ValidationError{Field: "patch", Message: "patch must look like 14.23"}
+	}
+
+	return patch, nil
+}
+
+// ValidateRole validates an optional role filter and returns the role name used by normalizeRole
+func ValidateRole(role string) (string, error) {
+	role = SanitizeString(role)
+	if role == "" {
+		return "", nil
+	}
+
+	switch strings.ToUpper(role) {
+	case "ARAM":
+		return "ARAM", nil
+	case "ARENA", "CHERRY":
+		return "Arena", nil
+	}
+
+	normalized := normalizeRole(role, "")
+	switch normalized {
+	case "Top", "Jungle", "Mid", "Bot", "Support":
+		return normalized, nil
+	}
+
+	return "", ValidationError{Field: "role", Message: "role must be one of top, jungle, mid, bot, support, aram or arena"}
+}
+
+// timezoneRegex matches IANA zone names such as Europe/Berlin, America/Argentina/Buenos_Aires or Etc/GMT+5
+var timezoneRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_+\-/]{0,63}$`)
+
+// ValidateTimezone validates an optional IANA timezone and loads it, defaulting to UTC
+func ValidateTimezone(timezone string) (*time.Location, error) {
+	timezone = SanitizeString(timezone)
+	if timezone == "" {
+		return time.UTC, nil
+	}
+
+	if !timezoneRegex.MatchString(timezone) {
+		return nil, ValidationError{Field: "tz", Message: "timezone must be an IANA name such as Europe/Berlin"}
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, ValidationError{Field: "tz", Message: fmt.Sprintf("unknown timezone %q", timezone)}
+	}
+
+	return loc, nil
+}
+
+// ValidateTimeRange validates optional startTime/endTime filters given as Unix seconds,
+// the same form Riot's match-v5 API uses. Zero means unbounded.
+func ValidateTimeRange(startStr, endStr string) (int64, int64, error) {
+	parse := func(field, value string) (int64, error) {
+		if value == "" {
+			return 0, nil
+		}
+		ts, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || ts < 0 {
+			return 0, ValidationError{Field: field, Message: field + " must be a non-negative Unix timestamp in seconds"}
+		}
+		return ts, nil
+	}
+
+	start, err := parse("startTime", startStr)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parse("endTime", endStr)
+	if err != nil {
+		return 0, 0, err
+	}
+	if start > 0 && end > 0 && end < start {
+		return 0, 0, ValidationError{Field: "endTime", Message: "endTime cannot be before startTime"}
+	}
+
+	return start, end, nil
+}
+
+// ValidateSearchQuery validates a Riot ID search prefix such as "fak" or "Faker#K"
+func ValidateSearchQuery(query string) (string, error) {
+	query = SanitizeString(query)
+	if query == "" {
+		return "", ValidationError{Field: "q", Message: "q cannot be empty"}
+	}
+
+	if utf8.RuneCountInString(query) > MaxSearchQueryLength {
+		return "", ValidationError{Field: "q", Message: fmt.Sprintf("q cannot be longer than %d characters", MaxSearchQueryLength)}
+	}
+
+	if strings.Count(query, "#") > 1 {
+		return "", ValidationError{Field: "q", Message: "q can contain at most one #"}
+	}
+
+	return query, nil
+}